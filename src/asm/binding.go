@@ -0,0 +1,647 @@
+package asm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/CFdefense/compiler/src/constant"
+	"github.com/CFdefense/compiler/src/diagnostics"
+	"github.com/CFdefense/compiler/src/lexer"
+	"github.com/CFdefense/compiler/src/parser"
+)
+
+// variable is a name the operands of extended asm can bind
+// kind is parameter, variable, constant or function
+type variable struct {
+	name      string
+	token     lexer.Token
+	kind      string
+	valueType valueType
+	decl      string // type and name as declared, for suggesting mut
+	mutable   bool
+}
+
+// valueType is the type of a bound expression as far as asm needs to know it
+// name is empty when the type is not known
+type valueType struct {
+	name     string
+	pointers int
+	array    bool
+	function bool
+}
+
+// constraint is what an operand constraint such as "=r" allows
+type constraint struct {
+	write     bool // = or +, the operand is an output
+	register  bool
+	memory    bool
+	immediate bool
+	vector    bool
+	pinned    string // the 64 bit register a letter such as a names
+	matches   int    // output an input shares its location with, -1 for none
+}
+
+// binding is an operand of extended asm once checked
+// size is the size of the register it is put in, 0 when not known
+type binding struct {
+	operand    *parser.AsmOperand
+	constraint constraint
+	name       string // the variable bound when the expression is just a name
+	size       int
+	memory     bool
+}
+
+// letters that pin an operand to one register
+var pinnedRegisters = map[byte]string{'a': "rax", 'b': "rbx", 'c': "rcx", 'd': "rdx", 'S': "rsi", 'D': "rdi"}
+
+// size in bits of each size modifier of a bound operand, %k0 is the 32 bit register
+var modifierSizes = map[byte]int{'b': 8, 'h': 8, 'w': 16, 'k': 32, 'q': 64}
+
+const constraintNote = "constraints are `r`, `m`, `g`, `i`, `n`, `x`, a register letter such as `a` or `D`, " +
+	"or the number of an output"
+
+func (v *Validator) pushScope() {
+	v.scopes = append(v.scopes, map[string]*variable{})
+}
+
+func (v *Validator) popScope() {
+	v.scopes = v.scopes[:len(v.scopes)-1]
+}
+
+// declare adds a parameter or variable to the innermost scope
+func (v *Validator) declare(declarator *parser.Declarator, typeNode *parser.TypeNode, mutable bool, kind string) {
+	if declarator == nil || declarator.Name == "" || typeNode == nil {
+		return
+	}
+	valueType := valueType{
+		name:     typeNode.Name,
+		pointers: typeNode.Pointers + declarator.Pointers,
+		array:    len(declarator.ArrayDims) > 0,
+		function: declarator.IsFuncPointer,
+	}
+	v.scopes[len(v.scopes)-1][declarator.Name] = &variable{
+		name:      declarator.Name,
+		token:     declarator.Token,
+		kind:      kind,
+		valueType: valueType,
+		decl:      typeNode.String() + " mut " + strings.Repeat("*", declarator.Pointers) + declarator.Name,
+		mutable:   mutable,
+	}
+}
+
+// declareGlobals adds the constants, functions and enum variants of a program to the outermost scope
+func (v *Validator) declareGlobals(program *parser.Program) {
+	global := v.scopes[0]
+	for _, decl := range program.Declarations {
+		switch decl := decl.(type) {
+		case *parser.ConstDecl:
+			constType := valueType{}
+			if decl.Type != nil {
+				constType = valueType{name: decl.Type.Name, pointers: decl.Type.Pointers}
+			}
+			global[decl.Name] = &variable{name: decl.Name, token: decl.Token, kind: "constant", valueType: constType}
+		case *parser.FunctionDecl:
+			global[decl.Name] = &variable{name: decl.Name, token: decl.Token, kind: "function",
+				valueType: valueType{function: true}}
+		case *parser.EnumDecl:
+			for _, variant := range decl.Variants {
+				global[variant.Name] = &variable{name: variant.Name, token: variant.Token, kind: "constant"}
+			}
+		case *parser.StructDecl:
+			v.structs[decl.Name] = true
+		}
+	}
+}
+
+// lookup finds the innermost variable with a name
+func (v *Validator) lookup(name string) (*variable, bool) {
+	for i := len(v.scopes) - 1; i >= 0; i-- {
+		if found, ok := v.scopes[i][name]; ok {
+			return found, true
+		}
+	}
+	return nil, false
+}
+
+// checkOperands checks the outputs, inputs and clobbers of extended asm
+// binding variables to registers is only allowed in an @unsafe function
+func (v *Validator) checkOperands(stmt *parser.AsmStatement) []binding {
+	if !isUnsafe(v.function) {
+		v.diagnostics.Error(diagnostics.E_UNSAFE_REQUIRED, tokenSpan(stmt.Token),
+			"asm operands outside of an `@unsafe` function").
+			WithLabel(fmt.Sprintf("binds variables of `%s` to registers", v.function.Name)).
+			WithNote(fmt.Sprintf("add `@unsafe` before the declaration of `%s`", v.function.Name))
+	}
+
+	var bindings []binding
+	for _, output := range stmt.Outputs {
+		bindings = append(bindings, v.checkBinding(output, true, len(stmt.Outputs)))
+	}
+	for _, input := range stmt.Inputs {
+		bindings = append(bindings, v.checkBinding(input, false, len(stmt.Outputs)))
+	}
+	v.checkClobbers(stmt, bindings)
+	return bindings
+}
+
+// isUnsafe checks if a function is decorated with @unsafe
+func isUnsafe(function *parser.FunctionDecl) bool {
+	for _, decorator := range function.Decorators {
+		if decorator.Name == "unsafe" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBinding checks the constraint of an operand and the expression it binds
+// an output must be a place that can be written, and its variable declared mut
+func (v *Validator) checkBinding(op *parser.AsmOperand, output bool, outputs int) binding {
+	bound := binding{operand: op}
+	if name, ok := op.Expr.(*parser.Identifier); ok {
+		bound.name = name.Name
+	}
+	v.checkNames(op.Expr)
+	if output {
+		v.checkWritable(op.Expr, op.Expr)
+	}
+
+	c, ok := v.checkConstraint(op, output, outputs)
+	bound.constraint = c
+	if ok {
+		v.checkType(op, &bound)
+	}
+	return bound
+}
+
+// parseConstraint reads the modifier and letters of a constraint
+// returns the first letter it does not know, if there is one
+func parseConstraint(text string) (constraint, string) {
+	c := constraint{matches: -1}
+	letters := text
+	if strings.HasPrefix(letters, "=") || strings.HasPrefix(letters, "+") {
+		c.write, letters = true, letters[1:]
+	}
+	for i := 0; i < len(letters); i++ {
+		letter := letters[i]
+		switch {
+		case letter == '&':
+			// early clobber, the output is written before every input is read
+		case letter == 'r' || letter == 'q' || letter == 'Q' || letter == 'R':
+			c.register = true
+		case pinnedRegisters[letter] != "":
+			c.register, c.pinned = true, pinnedRegisters[letter]
+		case letter == 'm' || letter == 'o':
+			c.memory = true
+		case letter == 'i' || letter == 'n':
+			c.immediate = true
+		case letter == 'g':
+			c.register, c.memory, c.immediate = true, true, true
+		case letter == 'x':
+			c.vector = true
+		case letter >= '0' && letter <= '9':
+			end := i
+			for end < len(letters) && letters[end] >= '0' && letters[end] <= '9' {
+				end++
+			}
+			c.matches, _ = strconv.Atoi(letters[i:end])
+			i = end - 1
+		default:
+			return c, letters[i : i+1]
+		}
+	}
+	return c, ""
+}
+
+// checkConstraint reports a constraint that is unknown or cannot be used by its operand
+func (v *Validator) checkConstraint(op *parser.AsmOperand, output bool, outputs int) (constraint, bool) {
+	c, unknown := parseConstraint(op.Constraint)
+	span := tokenSpan(op.Token)
+	var diagnostic *diagnostics.Diagnostic
+	switch {
+	case unknown != "":
+		diagnostic = v.diagnostics.Error(diagnostics.E_INVALID_CONSTRAINT, span,
+			"unknown constraint `%s` in `%s`", unknown, op.Constraint).
+			WithLabel("not an x86-64 operand constraint").
+			WithNote(constraintNote)
+	case output && !c.write:
+		diagnostic = v.diagnostics.Error(diagnostics.E_INVALID_CONSTRAINT, span,
+			"output constraint `%s` must start with `=` or `+`", op.Constraint).
+			WithLabel("`=` writes the output, `+` reads and writes it")
+	case !output && c.write:
+		diagnostic = v.diagnostics.Error(diagnostics.E_INVALID_CONSTRAINT, span,
+			"input constraint `%s` cannot write its operand", op.Constraint).
+			WithLabel("`=` and `+` only start output constraints")
+	case output && c.matches != -1:
+		diagnostic = v.diagnostics.Error(diagnostics.E_INVALID_CONSTRAINT, span,
+			"output constraint `%s` cannot match another operand", op.Constraint).
+			WithLabel("only an input can share the location of an output")
+	case c.matches >= outputs:
+		diagnostic = v.diagnostics.Error(diagnostics.E_INVALID_CONSTRAINT, span,
+			"constraint `%s` matches output %d but the asm has %d %s", op.Constraint, c.matches, outputs,
+			plural(outputs, "output", "outputs")).
+			WithLabel("no output to share a location with")
+	case !c.register && !c.memory && !c.immediate && !c.vector && c.matches == -1:
+		diagnostic = v.diagnostics.Error(diagnostics.E_INVALID_CONSTRAINT, span,
+			"constraint `%s` names no kind of operand", op.Constraint).
+			WithLabel("expected a letter such as `r` or `m`")
+	case output && c.immediate && !c.register && !c.memory:
+		diagnostic = v.diagnostics.Error(diagnostics.E_INVALID_CONSTRAINT, span,
+			"output constraint `%s` cannot be an immediate", op.Constraint).
+			WithLabel("an immediate is a constant the asm cannot write")
+	}
+	return c, diagnostic == nil
+}
+
+// checkNames reports the names an operand expression uses that are not in scope
+// block expressions are left alone, they declare names of their own
+func (v *Validator) checkNames(expr parser.Expression) {
+	switch expr := expr.(type) {
+	case *parser.Identifier:
+		if _, ok := v.lookup(expr.Name); !ok {
+			v.diagnostics.Error(diagnostics.E_INVALID_BINDING, tokenSpan(expr.Token),
+				"cannot find `%s` in this scope", expr.Name).
+				WithLabel("not declared before this asm")
+		}
+	case *parser.UnaryExpr:
+		v.checkNames(expr.Operand)
+	case *parser.BinaryExpr:
+		v.checkNames(expr.Left)
+		v.checkNames(expr.Right)
+	case *parser.AssignExpr:
+		v.checkNames(expr.Target)
+		v.checkNames(expr.Value)
+	case *parser.CallExpr:
+		v.checkNames(expr.Callee)
+		for _, argument := range expr.Arguments {
+			v.checkNames(argument)
+		}
+	case *parser.IndexExpr:
+		v.checkNames(expr.Array)
+		v.checkNames(expr.Index)
+	case *parser.MemberExpr:
+		v.checkNames(expr.Object)
+	case *parser.TernaryExpr:
+		v.checkNames(expr.Condition)
+		v.checkNames(expr.Then)
+		v.checkNames(expr.Else)
+	case *parser.CastExpr:
+		v.checkNames(expr.Operand)
+	case *parser.SizeofExpr:
+		if expr.Operand != nil {
+			v.checkNames(expr.Operand)
+		}
+	}
+}
+
+// checkWritable reports an output that cannot be written
+// writes through a pointer are allowed, otherwise the variable the place is part of must be mut
+func (v *Validator) checkWritable(expr, output parser.Expression) {
+	switch expr := expr.(type) {
+	case *parser.Identifier:
+		found, ok := v.lookup(expr.Name)
+		if !ok || found.mutable {
+			return
+		}
+		diagnostic := v.diagnostics.Error(diagnostics.E_IMMUTABLE_BINDING, exprSpan(output),
+			"cannot bind immutable `%s` to an asm output", expr.Name).
+			WithLabel("written by the asm")
+		switch found.kind {
+		case "constant", "function":
+			diagnostic.WithSecondary(tokenSpan(found.token), fmt.Sprintf("`%s` is a %s", found.name, found.kind))
+		default:
+			diagnostic.WithSecondary(tokenSpan(found.token), fmt.Sprintf("`%s` is declared here without `mut`", found.name)).
+				WithNote(fmt.Sprintf("declare it as `%s` to let the asm write it", found.decl))
+		}
+		return
+	case *parser.MemberExpr:
+		if !expr.Arrow {
+			v.checkWritable(expr.Object, output)
+		}
+		return
+	case *parser.IndexExpr:
+		if arrayType, ok := v.typeOf(expr.Array); !ok || arrayType.array || arrayType.pointers == 0 {
+			v.checkWritable(expr.Array, output)
+		}
+		return
+	case *parser.UnaryExpr:
+		if expr.Op == "*" && !expr.Postfix {
+			return
+		}
+	}
+	v.diagnostics.Error(diagnostics.E_INVALID_BINDING, exprSpan(expr),
+		"asm output is not a place that can be written").
+		WithLabel("expected a variable, field, element or dereference")
+}
+
+// checkType checks that what an operand binds fits where its constraint puts it
+// and works out the size of the register it is given
+func (v *Validator) checkType(op *parser.AsmOperand, bound *binding) {
+	c := bound.constraint
+	switch {
+	case c.matches != -1:
+		return
+	case c.immediate && !c.register && !c.memory:
+		if !v.isConstant(op.Expr) {
+			v.diagnostics.Error(diagnostics.E_INVALID_BINDING, exprSpan(op.Expr),
+				"constraint `%s` needs a constant", op.Constraint).
+				WithLabel("not known at compile time")
+		}
+		return
+	case c.memory && !c.register && !c.immediate:
+		bound.memory = true
+		if !isPlace(op.Expr) {
+			v.diagnostics.Error(diagnostics.E_INVALID_BINDING, exprSpan(op.Expr),
+				"constraint `%s` needs a value in memory", op.Constraint).
+				WithLabel("this expression has no address")
+		}
+		return
+	}
+
+	valueType, ok := v.typeOf(op.Expr)
+	if !ok {
+		return
+	}
+	who := "the value"
+	if bound.name != "" {
+		who = "`" + bound.name + "`"
+	}
+	size, problem, hint := v.registerSize(valueType)
+	switch {
+	case c.vector && !c.register:
+		// an sse register holds a float or any integer of up to 128 bits
+		if valueType.array || valueType.name == "big" || valueType.name == "void" || v.structs[valueType.name] {
+			v.diagnostics.Error(diagnostics.E_INVALID_BINDING, exprSpan(op.Expr),
+				"%s does not fit in an sse register", who).
+				WithLabel(who + " is " + problem)
+		}
+	case problem == "":
+		bound.size = size
+	case c.memory:
+		// the operand goes in memory when it does not fit in a register
+		bound.memory = true
+	default:
+		diagnostic := v.diagnostics.Error(diagnostics.E_INVALID_BINDING, exprSpan(op.Expr),
+			"%s does not fit in a general purpose register", who).
+			WithLabel(who + " is " + problem)
+		if hint != "" {
+			diagnostic.WithNote(hint)
+		}
+	}
+}
+
+// registerSize is the size of the general purpose register a value of a type
+// is put in, or what keeps it out of one and how else it could be bound
+func (v *Validator) registerSize(valueType valueType) (int, string, string) {
+	const inMemory = "bind it with `m` to pass it in memory"
+	switch {
+	case valueType.array:
+		return 0, "an array", inMemory
+	case valueType.function || valueType.pointers > 0:
+		return 64, "", ""
+	case valueType.name == "bool":
+		return 8, "", ""
+	case valueType.name == "float":
+		return 0, "a `float`", "bind it with `x` to use an sse register"
+	case valueType.name == "void":
+		return 0, "a `void`", ""
+	case v.structs[valueType.name]:
+		return 0, fmt.Sprintf("the struct `%s`", valueType.name), inMemory
+	}
+	intType, ok := constant.LookupIntType(valueType.name)
+	switch {
+	case !ok:
+		// an enum or a type asm cannot know about
+		return 0, "", ""
+	case intType.Bits == 0:
+		return 0, "a `big`, which has no fixed size", ""
+	case intType.Bits > 64:
+		return 0, fmt.Sprintf("%s `%s`, which is %d bits", typeArticle(intType.Name), intType.Name, intType.Bits), inMemory
+	}
+	return intType.Bits, "", ""
+}
+
+// typeArticle picks a or an for a type name
+func typeArticle(name string) string {
+	if strings.HasPrefix(name, "i") {
+		return "an"
+	}
+	return "a"
+}
+
+// typeOf works out the type of the expressions asm operands usually bind
+// returns false for the ones it cannot know without a type checker
+func (v *Validator) typeOf(expr parser.Expression) (valueType, bool) {
+	switch expr := expr.(type) {
+	case *parser.Identifier:
+		if found, ok := v.lookup(expr.Name); ok && (found.valueType.name != "" || found.valueType.function) {
+			return found.valueType, true
+		}
+	case *parser.Literal:
+		switch expr.Kind {
+		case lexer.T_INT_LITERAL:
+			if suffix := expr.Token.GetValue().GetSuffix(); suffix != "" {
+				return valueType{name: suffix}, !strings.HasPrefix(suffix, "f")
+			}
+			return valueType{name: "int"}, true
+		case lexer.T_FLOAT_LITERAL:
+			return valueType{name: "float"}, true
+		case lexer.T_BOOL_LITERAL:
+			return valueType{name: "bool"}, true
+		case lexer.T_STRING_LITERAL, lexer.T_RAW_STRING_LITERAL, lexer.T_BYTE_STRING_LITERAL:
+			return valueType{name: "u8", pointers: 1}, true
+		}
+	case *parser.CastExpr:
+		return valueType{name: expr.Type.Name, pointers: expr.Type.Pointers}, true
+	case *parser.UnaryExpr:
+		switch {
+		case expr.Op == "&" || expr.Op == "&mut":
+			return valueType{pointers: 1}, true
+		case expr.Op == "*" && !expr.Postfix:
+			if pointer, ok := v.typeOf(expr.Operand); ok && pointer.pointers > 0 && !pointer.array {
+				pointer.pointers--
+				return pointer, true
+			}
+		case expr.Op == "-" || expr.Op == "~" || expr.Op == "+":
+			return v.typeOf(expr.Operand)
+		}
+	}
+	return valueType{}, false
+}
+
+// isConstant checks if an expression is known at compile time
+func (v *Validator) isConstant(expr parser.Expression) bool {
+	switch expr := expr.(type) {
+	case *parser.Literal:
+		return expr.Kind != lexer.T_STRING_LITERAL && expr.Kind != lexer.T_RAW_STRING_LITERAL &&
+			expr.Kind != lexer.T_BYTE_STRING_LITERAL
+	case *parser.Identifier:
+		found, ok := v.lookup(expr.Name)
+		return ok && found.kind == "constant"
+	case *parser.UnaryExpr:
+		return expr.Op != "*" && expr.Op != "&" && expr.Op != "&mut" && expr.Op != "++" && expr.Op != "--" &&
+			v.isConstant(expr.Operand)
+	case *parser.BinaryExpr:
+		return v.isConstant(expr.Left) && v.isConstant(expr.Right)
+	case *parser.CastExpr:
+		return v.isConstant(expr.Operand)
+	case *parser.SizeofExpr:
+		return true
+	}
+	return false
+}
+
+// isPlace checks if an expression has an address
+func isPlace(expr parser.Expression) bool {
+	switch expr := expr.(type) {
+	case *parser.Identifier, *parser.MemberExpr, *parser.IndexExpr:
+		return true
+	case *parser.UnaryExpr:
+		return expr.Op == "*" && !expr.Postfix
+	}
+	return false
+}
+
+// checkClobbers checks the registers an asm says it overwrites
+// cc is the flags and memory any memory, a register an operand is pinned to
+// cannot also be clobbered
+func (v *Validator) checkClobbers(stmt *parser.AsmStatement, bindings []binding) {
+	for _, clobber := range stmt.Clobbers {
+		name := clobber.GetValue().GetString()
+		if name == "cc" || name == "memory" {
+			continue
+		}
+		register, ok := LookupRegister(strings.TrimPrefix(name, "%"))
+		if !ok {
+			diagnostic := v.diagnostics.Error(diagnostics.E_UNKNOWN_REGISTER, tokenSpan(clobber),
+				"unknown clobber `%s`", name).
+				WithLabel("not a register, `cc` or `memory`")
+			if suggestion, found := suggest(strings.ToLower(strings.TrimPrefix(name, "%")),
+				append([]string{"cc", "memory"}, registerNames...)); found {
+				diagnostic.WithNote(fmt.Sprintf("did you mean `%s`?", suggestion))
+			}
+			continue
+		}
+		for i, bound := range bindings {
+			if bound.constraint.pinned == "" || bound.constraint.pinned != register.Family {
+				continue
+			}
+			v.diagnostics.Error(diagnostics.E_INVALID_CONSTRAINT, tokenSpan(clobber),
+				"clobber `%s` conflicts with operand `%%%d`", name, i).
+				WithLabel("the asm overwrites this register").
+				WithSecondary(tokenSpan(bound.operand.Token), fmt.Sprintf("`%%%d` is bound to `%%%s` here", i, bound.constraint.pinned))
+		}
+	}
+}
+
+// checkTemplate checks the operands written in asm text as %0, %1 and so on
+// in extended asm a register is written with %%, which is read as a single %
+func (v *Validator) checkTemplate(src source, extended bool, operands []binding) source {
+	var text strings.Builder
+	var offsets []int
+	for i := 0; i < len(src.text); i++ {
+		offsets = append(offsets, i)
+		if src.text[i] != '%' {
+			text.WriteByte(src.text[i])
+			continue
+		}
+		rest := src.text[i:]
+		if extended && strings.HasPrefix(rest, "%%") {
+			text.WriteByte('%')
+			i++
+			continue
+		}
+		if match := placeholder.FindStringSubmatch(rest); match != nil {
+			if number, _ := strconv.Atoi(match[2]); number >= len(operands) {
+				diagnostic := v.diagnostics.Error(diagnostics.E_INVALID_OPERAND, src.span(i, i+len(match[0])),
+					"asm operand `%s` does not exist", match[0])
+				if len(operands) == 0 {
+					diagnostic.WithLabel("the asm binds no operands")
+				} else {
+					diagnostic.WithLabel(fmt.Sprintf("the asm binds %d %s", len(operands), plural(len(operands), "operand", "operands")))
+				}
+				if !extended {
+					diagnostic.WithNote("operands are bound after a `:` that follows the asm string")
+				}
+			}
+		} else if register := lexer.ASM_REGISTER.FindString(rest); extended && register != "" {
+			v.diagnostics.Error(diagnostics.E_INVALID_OPERAND, src.span(i, i+len(register)),
+				"registers are written `%%%s` in asm with operands", register).
+				WithLabel("a single `%` starts an operand number")
+		}
+		text.WriteByte('%')
+	}
+	offsets = append(offsets, len(src.text))
+
+	return source{
+		text:     text.String(),
+		operands: operands,
+		span: func(start, end int) diagnostics.Span {
+			return src.span(offsets[start], offsets[end])
+		},
+	}
+}
+
+// exprSpan covers an expression from its first token to its last
+// only the first token is covered when it runs over several lines
+func exprSpan(expr parser.Expression) diagnostics.Span {
+	first, last := firstToken(expr), lastToken(expr)
+	span := tokenSpan(first)
+	if last.GetEndRow() == first.GetRow() && last.GetEndCol() > first.GetCol() {
+		span.Length = last.GetEndCol() - first.GetCol()
+	}
+	return span
+}
+
+// tokenSpan builds a diagnostic span covering a token
+func tokenSpan(token lexer.Token) diagnostics.Span {
+	return diagnostics.Span{
+		File:   token.GetFile(),
+		Row:    token.GetRow(),
+		Col:    token.GetCol(),
+		Length: max(token.GetEndCol()-token.GetCol(), 1),
+	}
+}
+
+// firstToken finds the leftmost token of an expression
+func firstToken(expr parser.Expression) lexer.Token {
+	switch expr := expr.(type) {
+	case *parser.BinaryExpr:
+		return firstToken(expr.Left)
+	case *parser.AssignExpr:
+		return firstToken(expr.Target)
+	case *parser.TernaryExpr:
+		return firstToken(expr.Condition)
+	case *parser.CallExpr:
+		return firstToken(expr.Callee)
+	case *parser.IndexExpr:
+		return firstToken(expr.Array)
+	case *parser.MemberExpr:
+		return firstToken(expr.Object)
+	case *parser.UnaryExpr:
+		if expr.Postfix {
+			return firstToken(expr.Operand)
+		}
+	}
+	return expr.GetToken()
+}
+
+// lastToken finds the rightmost token of an expression that has one
+func lastToken(expr parser.Expression) lexer.Token {
+	switch expr := expr.(type) {
+	case *parser.BinaryExpr:
+		return lastToken(expr.Right)
+	case *parser.AssignExpr:
+		return lastToken(expr.Value)
+	case *parser.TernaryExpr:
+		return lastToken(expr.Else)
+	case *parser.UnaryExpr:
+		if !expr.Postfix {
+			return lastToken(expr.Operand)
+		}
+	case *parser.CastExpr:
+		return lastToken(expr.Operand)
+	}
+	return expr.GetToken()
+}
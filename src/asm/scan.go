@@ -0,0 +1,231 @@
+package asm
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/CFdefense/compiler/src/lexer"
+)
+
+// item is a piece of asm text, start and end are byte offsets into the text
+type item struct {
+	text  string
+	start int
+	end   int
+}
+
+// What kind of operand an instruction was given
+type OperandKind int
+
+const (
+	OPERAND_REGISTER  OperandKind = iota // %rax
+	OPERAND_IMMEDIATE                    // $42
+	OPERAND_MEMORY                       // -8(%rbp, %rcx, 4)
+	OPERAND_SYMBOL                       // a label or an absolute address
+	OPERAND_MISSING                      // nothing written after a comma
+	OPERAND_BOUND                        // %0, an operand of extended asm
+)
+
+// operand is one operand of an instruction
+// base, index and scale are only set for the parts a memory reference has
+type operand struct {
+	item
+	kind      OperandKind
+	indirect  bool // * before the target of a call or jmp
+	register  item // the register of a register operand
+	segment   item // segment register before a :
+	base      item
+	index     item
+	scale     item
+	malformed bool // memory reference that is not disp(base, index, scale)
+	bound     int  // number of a bound operand
+	modifier  byte // size modifier of a bound operand, as the k of %k0
+}
+
+// statement is a single instruction written in asm text
+type statement struct {
+	mnemonic item
+	operands []operand
+}
+
+// segment override written before an address, as in %fs:0x28
+var segmentOverride = regexp.MustCompile(`^%[a-zA-Z]+:`)
+
+// operand of extended asm written in its template, %0 or %k0 with a size modifier
+var placeholder = regexp.MustCompile(`^%([bhwkqc]?)([0-9]+)`)
+var placeholders = regexp.MustCompile(`%[bhwkqc]?[0-9]+`)
+
+// scan splits asm text into the instructions written in it
+// instructions end at a newline or a ; and # starts a comment. labels and
+// prefixes are skipped and so is every directive, which starts with a .
+func scan(text string) []statement {
+	var statements []statement
+	for i := 0; i < len(text); {
+		i = skipBlanks(text, i)
+		if i >= len(text) {
+			break
+		}
+		switch c := text[i]; {
+		case c == '\n' || c == ';':
+			i++
+			continue
+		case c == '#':
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+			continue
+		}
+		if label := lexer.ASM_LABEL.FindString(text[i:]); label != "" {
+			i += len(label)
+			continue
+		}
+
+		word := lexer.ASM_INSTRUCTION.FindString(text[i:])
+		if lexer.ASM_PREFIXES[strings.ToLower(word)] {
+			i += len(word)
+			continue
+		}
+		if strings.HasPrefix(word, ".") {
+			i = statementEnd(text, i)
+			continue
+		}
+		if word == "" {
+			// something that is not a word where the instruction belongs
+			word = text[i:wordEnd(text, i)]
+		}
+		stmt := statement{mnemonic: item{word, i, i + len(word)}}
+		i = scanOperands(text, i+len(word), &stmt)
+		statements = append(statements, stmt)
+	}
+	return statements
+}
+
+// scanOperands reads the comma separated operands after a mnemonic
+// commas inside the parentheses of a memory reference do not separate operands
+func scanOperands(text string, i int, stmt *statement) int {
+	lastComma := -1
+	for {
+		i = skipBlanks(text, i)
+		start, depth := i, 0
+		for i < len(text) && text[i] != '\n' && text[i] != ';' && text[i] != '#' && (depth > 0 || text[i] != ',') {
+			switch text[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			i++
+		}
+		written := strings.TrimRight(text[start:i], " \t\r")
+		comma := i < len(text) && text[i] == ','
+		switch {
+		case written != "":
+			stmt.operands = append(stmt.operands, parseOperand(item{written, start, start + len(written)}))
+		case comma || len(stmt.operands) > 0:
+			// a missing operand is shown at the comma next to it
+			at := lastComma
+			if comma {
+				at = i
+			}
+			stmt.operands = append(stmt.operands, operand{item: item{"", at, at + 1}, kind: OPERAND_MISSING})
+		}
+		if !comma {
+			return i
+		}
+		lastComma = i
+		i++
+	}
+}
+
+// parseOperand works out what kind of operand some text is
+func parseOperand(written item) operand {
+	op := operand{item: written}
+	text, offset := written.text, written.start
+	if strings.HasPrefix(text, "*") {
+		op.indirect = true
+		text, offset = text[1:], offset+1
+	}
+
+	// %k0 is a bound operand even though it looks like a register
+	if match := placeholder.FindStringSubmatch(text); match != nil && match[0] == text {
+		op.kind = OPERAND_BOUND
+		op.bound, _ = strconv.Atoi(match[2])
+		if match[1] != "" {
+			op.modifier = match[1][0]
+		}
+		return op
+	}
+	switch {
+	case strings.HasPrefix(text, "$"):
+		op.kind = OPERAND_IMMEDIATE
+		return op
+	case strings.HasPrefix(text, "%") && lexer.ASM_REGISTER.FindString(text) == text:
+		op.kind = OPERAND_REGISTER
+		op.register = item{text, offset, offset + len(text)}
+		return op
+	}
+
+	// a segment override such as %fs: comes before the address
+	if segment := segmentOverride.FindString(text); segment != "" {
+		op.segment = item{segment[:len(segment)-1], offset, offset + len(segment) - 1}
+		text, offset = text[len(segment):], offset+len(segment)
+	}
+
+	// without parentheses it is an absolute address or a label
+	open := strings.IndexByte(text, '(')
+	if open == -1 {
+		op.kind = OPERAND_SYMBOL
+		if op.segment.text != "" {
+			op.kind = OPERAND_MEMORY
+		}
+		return op
+	}
+	op.kind = OPERAND_MEMORY
+	// a bound operand may be the base or index, it is a register once substituted
+	substituted := placeholders.ReplaceAllString(text, "%p")
+	op.malformed = lexer.ASM_MEMORY_REF.FindString(substituted) != substituted
+	inside := strings.TrimSuffix(text[open+1:], ")")
+	parts := strings.Split(inside, ",")
+	at := offset + open + 1
+	for i, part := range parts {
+		trimmed := strings.TrimSpace(part)
+		piece := item{trimmed, at + strings.Index(part, trimmed), 0}
+		piece.end = piece.start + len(trimmed)
+		switch i {
+		case 0:
+			op.base = piece
+		case 1:
+			op.index = piece
+		case 2:
+			op.scale = piece
+		}
+		at += len(part) + 1
+	}
+	return op
+}
+
+// skipBlanks returns the position of the next character that is not a space or tab
+func skipBlanks(text string, i int) int {
+	for i < len(text) && (text[i] == ' ' || text[i] == '\t' || text[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// statementEnd returns the position of the newline or ; that ends the statement at i
+func statementEnd(text string, i int) int {
+	for i < len(text) && text[i] != '\n' && text[i] != ';' {
+		i++
+	}
+	return i
+}
+
+// wordEnd returns the position of the next blank or separator after i
+func wordEnd(text string, i int) int {
+	end := i + 1
+	for end < len(text) && !strings.ContainsRune(" \t\r\n;,#", rune(text[end])) {
+		end++
+	}
+	return end
+}
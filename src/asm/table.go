@@ -0,0 +1,272 @@
+package asm
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Instruction describes the operands an x86-64 mnemonic takes in AT&T syntax
+// Suffixes lists the size suffixes the mnemonic may be written with, from "bwlqst"
+// SameSize instructions work on one operand size, so every general purpose
+// register they are given must be that size
+type Instruction struct {
+	Name     string
+	Min      int
+	Max      int
+	Suffixes string
+	SameSize bool
+}
+
+// operand size of each size suffix in bits
+// x87 instructions write single precision as s and extended precision as t
+var suffixSizes = map[byte]int{'b': 8, 'w': 16, 'l': 32, 'q': 64, 's': 32, 't': 80}
+
+// instructions by mnemonic without a size suffix
+var instructions = map[string]Instruction{}
+
+// every mnemonic that may be written, suggested for ones that are misspelled
+var mnemonicNames []string
+
+// condition codes of the jcc, setcc and cmovcc families
+var conditions = []string{
+	"o", "no", "b", "nb", "c", "nc", "nae", "ae", "e", "z", "ne", "nz", "be", "na", "a", "nbe",
+	"s", "ns", "p", "pe", "np", "po", "l", "nge", "ge", "nl", "le", "ng", "g", "nle",
+}
+
+func init() {
+	add := func(min, max int, suffixes string, sameSize bool, names ...string) {
+		for _, name := range names {
+			instructions[name] = Instruction{Name: name, Min: min, Max: max, Suffixes: suffixes, SameSize: sameSize}
+		}
+	}
+
+	// data movement
+	add(2, 2, "bwlq", true, "mov", "xchg", "cmpxchg", "xadd")
+	add(2, 2, "q", false, "movabs")
+	add(2, 2, "wlq", true, "lea")
+	add(2, 2, "", false, "movzbw", "movzbl", "movzbq", "movzwl", "movzwq",
+		"movsbw", "movsbl", "movsbq", "movswl", "movswq", "movslq")
+	add(1, 1, "wq", false, "push", "pop")
+	add(0, 0, "wq", false, "pushf", "popf")
+
+	// arithmetic and logic
+	add(2, 2, "bwlq", true, "add", "sub", "adc", "sbb", "and", "or", "xor", "cmp", "test")
+	add(1, 1, "bwlq", true, "inc", "dec", "neg", "not", "mul", "div", "idiv")
+	add(1, 3, "wlq", true, "imul")
+	add(1, 2, "bwlq", false, "shl", "shr", "sal", "sar", "rol", "ror", "rcl", "rcr")
+	add(2, 2, "wlq", false, "bt", "bts", "btr", "btc")
+	add(2, 2, "wlq", true, "bsf", "bsr", "popcnt", "lzcnt", "tzcnt")
+	add(1, 1, "lq", true, "bswap")
+	add(0, 0, "", false, "cbw", "cwde", "cdqe", "cwd", "cdq", "cqo", "cbtw", "cwtl", "cltq", "cwtd", "cltd", "cqto")
+
+	// control flow
+	add(1, 1, "q", false, "call", "jmp")
+	add(0, 1, "q", false, "ret")
+	add(0, 0, "q", false, "leave")
+	add(1, 1, "", false, "jcxz", "jecxz", "jrcxz", "loop", "loope", "loopne")
+	for _, condition := range conditions {
+		add(1, 1, "", false, "j"+condition)
+		add(1, 1, "", false, "set"+condition)
+		add(2, 2, "wlq", true, "cmov"+condition)
+	}
+
+	// string instructions, movs and cmps also have the sse forms below
+	add(0, 0, "bwlq", false, "stos", "lods", "scas", "cmps")
+	add(0, 0, "", false, "movsb", "movsw", "movsl", "movsq")
+
+	// system and miscellaneous
+	add(0, 0, "", false, "syscall", "sysret", "hlt", "int3", "ud2", "cpuid", "rdtsc", "rdtscp",
+		"pause", "mfence", "lfence", "sfence", "clc", "stc", "cmc", "cld", "std", "cli", "sti")
+	add(1, 1, "", false, "int")
+	add(0, 1, "wl", false, "nop")
+
+	// sse scalar and packed floating point
+	add(2, 2, "", false, "movd", "movss", "movaps", "movups", "movapd", "movupd", "movdqa", "movdqu",
+		"addss", "addsd", "subss", "subsd", "mulss", "mulsd", "divss", "divsd", "sqrtss", "sqrtsd",
+		"minss", "minsd", "maxss", "maxsd", "addps", "addpd", "subps", "subpd", "mulps", "mulpd",
+		"divps", "divpd", "andps", "andpd", "orps", "orpd", "xorps", "xorpd", "ucomiss", "ucomisd",
+		"comiss", "comisd", "cvtss2sd", "cvtsd2ss", "pxor", "pand", "por", "paddb", "paddw", "paddd",
+		"paddq", "psubb", "psubw", "psubd", "psubq")
+	add(2, 2, "", false, "movhlps", "movlhps", "movmskps", "movmskpd", "pmovmskb", "unpcklps", "unpckhps",
+		"unpcklpd", "unpckhpd", "punpcklbw", "punpcklwd", "punpckldq", "punpcklqdq", "punpckhbw",
+		"punpckhwd", "punpckhdq", "punpckhqdq", "pcmpeqb", "pcmpeqw", "pcmpeqd", "pcmpgtb", "pcmpgtw",
+		"pcmpgtd", "pmullw", "pmulld", "pandn", "andnps", "andnpd", "sqrtps", "sqrtpd", "cvtdq2ps",
+		"cvtps2dq", "cvttps2dq", "cvtdq2pd", "cvtpd2dq", "cvtps2pd", "cvtpd2ps", "psllw", "pslld",
+		"psllq", "psrlw", "psrld", "psrlq", "psraw", "psrad", "pslldq", "psrldq")
+	add(3, 3, "", false, "pshufd", "pshuflw", "pshufhw", "shufps", "shufpd", "cmpss", "cmpsd", "cmpps", "cmppd")
+	add(2, 2, "lq", false, "cvtsi2ss", "cvtsi2sd", "cvttss2si", "cvttsd2si", "cvtss2si", "cvtsd2si")
+
+	// x87 floating point
+	add(1, 1, "slt", false, "fld", "fstp")
+	add(1, 1, "sl", false, "fst")
+	add(1, 1, "slq", false, "fild", "fistp", "fisttp")
+	add(1, 1, "sl", false, "fist")
+	add(0, 2, "sl", false, "fadd", "fsub", "fsubr", "fmul", "fdiv", "fdivr", "fcom", "fcomp")
+	add(0, 2, "", false, "faddp", "fsubp", "fsubrp", "fmulp", "fdivp", "fdivrp", "fcomi", "fcomip",
+		"fucomi", "fucomip", "fucom", "fucomp")
+	add(0, 1, "", false, "fxch")
+	add(0, 0, "", false, "fchs", "fabs", "fsqrt", "fsin", "fcos", "fldz", "fld1", "fldpi", "finit",
+		"fninit", "fwait", "fcompp", "fucompp")
+	// movsd is both the string instruction and the sse move
+	add(0, 2, "", false, "movsd")
+
+	mnemonicNames = mnemonics()
+}
+
+// LookupInstruction finds the instruction a mnemonic names
+// returns the operand size its suffix gives in bits, 0 when it has none
+func LookupInstruction(mnemonic string) (Instruction, int, bool) {
+	name := strings.ToLower(mnemonic)
+	if instruction, ok := instructions[name]; ok {
+		return instruction, 0, true
+	}
+	if len(name) < 2 {
+		return Instruction{}, 0, false
+	}
+	suffix := name[len(name)-1]
+	instruction, ok := instructions[name[:len(name)-1]]
+	if !ok || !strings.ContainsRune(instruction.Suffixes, rune(suffix)) {
+		return Instruction{}, 0, false
+	}
+	return instruction, suffixSizes[suffix], true
+}
+
+// What kind of register a register name is
+type RegisterClass int
+
+const (
+	REG_GENERAL RegisterClass = iota // rax, eax, ax, al and the others like them
+	REG_IP                           // rip and eip, only allowed as the base of a memory reference
+	REG_SEGMENT                      // cs, ds, es, fs, gs and ss
+	REG_VECTOR                       // mmx, xmm and ymm registers
+	REG_X87                          // st and st(0) to st(7)
+)
+
+// Register is a register name and its size in bits
+// Family is the 64 bit register a general purpose register is part of, rax for al
+type Register struct {
+	Name   string
+	Size   int
+	Class  RegisterClass
+	Family string
+}
+
+// registers by name without the %
+var registers = map[string]Register{}
+
+// names of the registers in the order they are suggested
+var registerNames []string
+
+func init() {
+	add := func(size int, class RegisterClass, names ...string) {
+		for _, name := range names {
+			registers[name] = Register{Name: name, Size: size, Class: class}
+			registerNames = append(registerNames, name)
+		}
+	}
+	setFamily := func(name, family string) {
+		register := registers[name]
+		register.Family = family
+		registers[name] = register
+	}
+
+	// the legacy registers of each size, in the order of the 64 bit register they are part of
+	legacy := [][]string{
+		{"rax", "rbx", "rcx", "rdx", "rsi", "rdi", "rbp", "rsp"},
+		{"eax", "ebx", "ecx", "edx", "esi", "edi", "ebp", "esp"},
+		{"ax", "bx", "cx", "dx", "si", "di", "bp", "sp"},
+		{"al", "bl", "cl", "dl", "sil", "dil", "bpl", "spl", "ah", "bh", "ch", "dh"},
+	}
+	add(64, REG_GENERAL, legacy[0]...)
+	add(32, REG_GENERAL, legacy[1]...)
+	add(16, REG_GENERAL, legacy[2]...)
+	add(8, REG_GENERAL, legacy[3]...)
+	for _, names := range legacy {
+		for i, name := range names {
+			// ah to dh come after the eight low bytes and are part of rax to rdx
+			setFamily(name, legacy[0][i%8])
+		}
+	}
+	for i := 8; i <= 15; i++ {
+		name := "r" + strconv.Itoa(i)
+		add(64, REG_GENERAL, name)
+		add(32, REG_GENERAL, name+"d")
+		add(16, REG_GENERAL, name+"w")
+		add(8, REG_GENERAL, name+"b")
+		for _, part := range []string{name, name + "d", name + "w", name + "b"} {
+			setFamily(part, name)
+		}
+	}
+	add(64, REG_IP, "rip")
+	add(32, REG_IP, "eip")
+	add(16, REG_SEGMENT, "cs", "ds", "es", "fs", "gs", "ss")
+	for i := 0; i <= 15; i++ {
+		add(128, REG_VECTOR, "xmm"+strconv.Itoa(i))
+		add(256, REG_VECTOR, "ymm"+strconv.Itoa(i))
+	}
+	for i := 0; i <= 7; i++ {
+		add(64, REG_VECTOR, "mm"+strconv.Itoa(i))
+		add(80, REG_X87, "st("+strconv.Itoa(i)+")")
+	}
+	add(80, REG_X87, "st")
+}
+
+// LookupRegister finds a register by its name without the %
+func LookupRegister(name string) (Register, bool) {
+	register, ok := registers[strings.ToLower(name)]
+	return register, ok
+}
+
+// suggest finds the candidate closest to a misspelled name
+// only a candidate at most two edits away and closer than the length of the name
+// is suggested, the earliest one wins a tie
+func suggest(name string, candidates []string) (string, bool) {
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if distance := editDistance(name, candidate); distance < bestDistance && distance < len(name) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance counts the insertions, deletions, substitutions and swaps of
+// two neighbouring characters that turn one name into the other
+func editDistance(a, b string) int {
+	distance := make([][]int, len(a)+1)
+	for i := range distance {
+		distance[i] = make([]int, len(b)+1)
+		distance[i][0] = i
+	}
+	for j := range distance[0] {
+		distance[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			distance[i][j] = min(distance[i-1][j]+1, distance[i][j-1]+1, distance[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				distance[i][j] = min(distance[i][j], distance[i-2][j-2]+1)
+			}
+		}
+	}
+	return distance[len(a)][len(b)]
+}
+
+// mnemonics lists every mnemonic with and without its suffixes
+// sorted so suggestions do not depend on map order
+func mnemonics() []string {
+	var names []string
+	for name, instruction := range instructions {
+		names = append(names, name)
+		for _, suffix := range instruction.Suffixes {
+			names = append(names, name+string(suffix))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
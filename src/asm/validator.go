@@ -0,0 +1,492 @@
+package asm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	debugger "github.com/CFdefense/compiler/src/debug"
+	"github.com/CFdefense/compiler/src/diagnostics"
+	"github.com/CFdefense/compiler/src/lexer"
+	"github.com/CFdefense/compiler/src/parser"
+)
+
+// Validator context object
+// checks the instructions of every asm statement against the x86-64 table
+// so mistakes are reported where they were written instead of by the assembler
+// the variables in scope are tracked for the operands of extended asm
+type Validator struct {
+	debug       *debugger.Debug
+	diagnostics *diagnostics.Reporter
+	scopes      []map[string]*variable
+	structs     map[string]bool
+	function    *parser.FunctionDecl
+}
+
+// Validator object constructor
+func InitializeValidator(debug bool) *Validator {
+	return &Validator{
+		debug:       debugger.InitializeDebugger("ASM", debug),
+		diagnostics: diagnostics.InitializeReporter(debug),
+	}
+}
+
+// Function responsible for all things inline assembly validation
+// every asm statement of every function is checked
+func (v *Validator) AsmValidation(program *parser.Program) {
+	v.debug.DebugLog(fmt.Sprintf("asm: validating %d declarations", len(program.Declarations)), false)
+	v.scopes, v.structs = nil, map[string]bool{}
+	v.pushScope()
+	v.declareGlobals(program)
+	for _, decl := range program.Declarations {
+		if function, ok := decl.(*parser.FunctionDecl); ok && function.Body != nil {
+			v.function = function
+			v.pushScope()
+			for _, param := range function.Params {
+				v.declare(param.Declarator, param.Type, param.Mutable, "parameter")
+			}
+			v.checkStatement(function.Body)
+			v.popScope()
+		}
+	}
+}
+
+// Function to reset a validator
+// mostly used in repeated test executions
+func (v *Validator) ResetValidator() {
+	v.diagnostics.ResetReporter()
+}
+
+// Function to get the diagnostics reported while validating
+func (v *Validator) GetReporter() *diagnostics.Reporter {
+	return v.diagnostics
+}
+
+// Function to share a diagnostics reporter with the other compiler phases
+func (v *Validator) SetReporter(reporter *diagnostics.Reporter) {
+	v.diagnostics = reporter
+}
+
+// checkStatement looks for asm statements
+// variables are declared as they are reached, so an asm only sees the ones before it
+func (v *Validator) checkStatement(stmt parser.Statement) {
+	switch stmt := stmt.(type) {
+	case *parser.Block:
+		v.pushScope()
+		for _, inner := range stmt.Statements {
+			v.checkStatement(inner)
+		}
+		v.popScope()
+	case *parser.VarDecl:
+		for _, declarator := range stmt.Declarators {
+			v.declare(declarator, stmt.Type, stmt.Mutable, "variable")
+		}
+	case *parser.AsmStatement:
+		var operands []binding
+		if stmt.Extended {
+			operands = v.checkOperands(stmt)
+		}
+		for _, line := range stmt.Lines {
+			v.checkLine(line, stmt.Extended, operands)
+		}
+	case *parser.IfStatement:
+		v.checkStatement(stmt.Then)
+		if stmt.Else != nil {
+			v.checkStatement(stmt.Else)
+		}
+	case *parser.WhileStatement:
+		v.checkStatement(stmt.Body)
+	case *parser.DoWhileStatement:
+		v.checkStatement(stmt.Body)
+	case *parser.ForStatement:
+		v.pushScope()
+		if stmt.Init != nil {
+			v.checkStatement(stmt.Init)
+		}
+		v.checkStatement(stmt.Body)
+		v.popScope()
+	case *parser.LabeledStatement:
+		v.checkStatement(stmt.Body)
+	case *parser.MatchStatement:
+		for _, arm := range stmt.Arms {
+			if body, ok := arm.Body.(parser.Statement); ok {
+				v.checkStatement(body)
+			}
+		}
+	}
+}
+
+// source is asm text and a way to find where each part of it was written
+// operands are what the %0, %1 and so on written in it are bound to
+type source struct {
+	text     string
+	span     func(start, end int) diagnostics.Span
+	operands []binding
+}
+
+// checkLine checks every instruction of a line of an asm statement
+func (v *Validator) checkLine(line *parser.AsmLine, extended bool, operands []binding) {
+	var src source
+	if line.Tokens == nil {
+		src = v.stringSource(line.Token)
+	} else {
+		src = v.lineSource(line.Tokens)
+	}
+	src = v.checkTemplate(src, extended, operands)
+	for _, stmt := range scan(src.text) {
+		v.checkInstruction(stmt, src)
+	}
+}
+
+// lineSource rebuilds the text of an instruction line of an asm block from its tokens
+// the tokens are all on one line, so the space between them is put back by column
+func (v *Validator) lineSource(tokens []lexer.Token) source {
+	first := tokens[0]
+	var text strings.Builder
+	col := first.GetCol()
+	for _, token := range tokens {
+		text.WriteString(strings.Repeat(" ", max(token.GetCol()-col, 0)))
+		text.WriteString(token.GetTokenContent())
+		col = token.GetEndCol()
+	}
+	written := text.String()
+	return source{
+		text: written,
+		span: func(start, end int) diagnostics.Span {
+			return diagnostics.Span{
+				File:   first.GetFile(),
+				Row:    first.GetRow(),
+				Col:    first.GetCol() + v.width(written[:start]),
+				Length: max(v.width(written[start:end]), 1),
+			}
+		},
+	}
+}
+
+// stringSource gives the asm text held by a string literal
+// \n and \t escapes separate instructions and operands the way they would for
+// the assembler, and every byte keeps the offset it was written at in the literal
+func (v *Validator) stringSource(token lexer.Token) source {
+	lexeme := token.GetTokenContent()
+	open, closing := 1, 1
+	if token.GetTokenType() == lexer.T_RAW_STRING_LITERAL {
+		hashes := len(lexeme) - len(strings.TrimLeft(lexeme[1:], "#")) - 1
+		open, closing = 2+hashes, 1+hashes
+	}
+
+	var text strings.Builder
+	var offsets []int
+	body := lexeme[open : len(lexeme)-closing]
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		offsets = append(offsets, open+i)
+		if c == '\\' && token.GetTokenType() == lexer.T_STRING_LITERAL && i+1 < len(body) {
+			i++
+			switch body[i] {
+			case 'n':
+				c = '\n'
+			case 't':
+				c = ' '
+			default:
+				c = body[i]
+			}
+		}
+		text.WriteByte(c)
+	}
+	offsets = append(offsets, len(lexeme)-closing)
+
+	return source{
+		text: text.String(),
+		span: func(start, end int) diagnostics.Span {
+			from, to := offsets[start], offsets[end]
+			span := diagnostics.Span{File: token.GetFile(), Row: token.GetRow(), Col: token.GetCol(),
+				Length: max(v.width(lexeme[from:to]), 1)}
+			before := lexeme[:from]
+			if line := strings.LastIndexByte(before, '\n'); line != -1 {
+				span.Row += strings.Count(before, "\n")
+				span.Col = 1
+				before = before[line+1:]
+			}
+			span.Col += v.width(before)
+			return span
+		},
+	}
+}
+
+// width is how many columns some text on one line takes up
+func (v *Validator) width(text string) int {
+	if v.diagnostics.GetColumnUnit() == diagnostics.COLUMN_BYTES {
+		return len(text)
+	}
+	return utf8.RuneCountInString(text)
+}
+
+// checkInstruction checks the mnemonic, operand count, registers and operand sizes of an instruction
+func (v *Validator) checkInstruction(stmt statement, src source) {
+	mnemonic := stmt.mnemonic.text
+	instruction, size, known := LookupInstruction(mnemonic)
+	if !known {
+		diagnostic := v.diagnostics.Error(diagnostics.E_UNKNOWN_INSTRUCTION, src.span(stmt.mnemonic.start, stmt.mnemonic.end),
+			"unknown instruction `%s`", mnemonic).
+			WithLabel("not an x86-64 instruction")
+		if base, ok := instructions[strings.ToLower(mnemonic[:len(mnemonic)-1])]; ok {
+			diagnostic.WithNote(suffixNote(base))
+		} else if suggestion, ok := suggest(strings.ToLower(mnemonic), mnemonicNames); ok {
+			diagnostic.WithNote(fmt.Sprintf("did you mean `%s`?", suggestion))
+		}
+	}
+
+	registersOk := true
+	for _, op := range stmt.operands {
+		registersOk = v.checkOperand(op, src) && registersOk
+	}
+	if !known {
+		return
+	}
+
+	if count := len(stmt.operands); count < instruction.Min || count > instruction.Max {
+		span := src.span(stmt.mnemonic.start, stmt.mnemonic.end)
+		if count > instruction.Max {
+			extra := stmt.operands[instruction.Max]
+			span = src.span(extra.start, extra.end)
+		}
+		v.diagnostics.Error(diagnostics.E_OPERAND_COUNT, span,
+			"`%s` takes %s but %d %s given", mnemonic, operandCount(instruction), count, plural(count, "was", "were")).
+			WithLabel(fmt.Sprintf("expected %s", operandCount(instruction)))
+		return
+	}
+	if registersOk {
+		v.checkSizes(stmt, instruction, size, src)
+	}
+}
+
+// checkOperand checks the registers an operand names
+// returns false if one of them was reported
+func (v *Validator) checkOperand(op operand, src source) bool {
+	switch op.kind {
+	case OPERAND_MISSING:
+		v.diagnostics.Error(diagnostics.E_INVALID_OPERAND, src.span(op.start, op.end),
+			"missing operand").
+			WithLabel("expected an operand next to this comma")
+		return false
+	case OPERAND_REGISTER:
+		register, ok := v.checkRegister(op.register, src)
+		if ok && register.Class == REG_IP {
+			v.diagnostics.Error(diagnostics.E_INVALID_OPERAND, src.span(op.register.start, op.register.end),
+				"`%s` cannot be used as an operand", op.register.text).
+				WithLabel("only allowed as the base of a memory reference").
+				WithNote(fmt.Sprintf("write `symbol(%s)` to address relative to the instruction", op.register.text))
+			return false
+		}
+		return ok
+	case OPERAND_MEMORY:
+		return v.checkMemory(op, src)
+	}
+	return true
+}
+
+// checkRegister looks up a register, reporting it if it does not exist
+func (v *Validator) checkRegister(name item, src source) (Register, bool) {
+	register, ok := LookupRegister(strings.TrimPrefix(name.text, "%"))
+	if ok {
+		return register, true
+	}
+	diagnostic := v.diagnostics.Error(diagnostics.E_UNKNOWN_REGISTER, src.span(name.start, name.end),
+		"unknown register `%s`", name.text).
+		WithLabel("not an x86-64 register")
+	if suggestion, found := suggest(strings.ToLower(strings.TrimPrefix(name.text, "%")), registerNames); found {
+		diagnostic.WithNote(fmt.Sprintf("did you mean `%%%s`?", suggestion))
+	}
+	return Register{}, false
+}
+
+// checkMemory checks the segment, base, index and scale of a memory reference
+// the base and index are 64 or 32 bit general purpose registers, the base may also
+// be %rip, and the scale is 1, 2, 4 or 8
+func (v *Validator) checkMemory(op operand, src source) bool {
+	if op.malformed {
+		v.diagnostics.Error(diagnostics.E_INVALID_OPERAND, src.span(op.start, op.end),
+			"malformed memory reference `%s`", op.text).
+			WithLabel("expected `displacement(base, index, scale)`")
+		return false
+	}
+
+	ok := true
+	if op.segment.text != "" {
+		if register, found := v.checkRegister(op.segment, src); !found {
+			ok = false
+		} else if register.Class != REG_SEGMENT {
+			v.diagnostics.Error(diagnostics.E_INVALID_OPERAND, src.span(op.segment.start, op.segment.end),
+				"`%s` is not a segment register", op.segment.text).
+				WithLabel("expected one of `%cs`, `%ds`, `%es`, `%fs`, `%gs` or `%ss`")
+			ok = false
+		}
+	}
+	for _, part := range []item{op.base, op.index} {
+		if part.text == "" || placeholder.MatchString(part.text) {
+			continue
+		}
+		register, found := v.checkRegister(part, src)
+		switch {
+		case !found:
+			ok = false
+		case register.Class == REG_IP && part == op.base:
+		case register.Class != REG_GENERAL || register.Size < 32:
+			v.diagnostics.Error(diagnostics.E_INVALID_OPERAND, src.span(part.start, part.end),
+				"`%s` cannot address memory", part.text).
+				WithLabel("addresses need a 64 or 32 bit general purpose register")
+			ok = false
+		case part == op.index && (register.Name == "rsp" || register.Name == "esp"):
+			v.diagnostics.Error(diagnostics.E_INVALID_OPERAND, src.span(part.start, part.end),
+				"`%s` cannot be an index register", part.text).
+				WithLabel("the stack pointer can only be the base")
+			ok = false
+		}
+	}
+	if op.scale.text != "" && op.scale.text != "1" && op.scale.text != "2" && op.scale.text != "4" && op.scale.text != "8" {
+		v.diagnostics.Error(diagnostics.E_INVALID_OPERAND, src.span(op.scale.start, op.scale.end),
+			"invalid scale `%s`", op.scale.text).
+			WithLabel("the scale must be 1, 2, 4 or 8")
+		ok = false
+	}
+	return ok
+}
+
+// checkSizes checks the operands of an instruction against its operand size
+// the size comes from the suffix, or from the first general purpose register
+// operand when there is no suffix
+func (v *Validator) checkSizes(stmt statement, instruction Instruction, size int, src source) {
+	mnemonic := stmt.mnemonic.text
+	var sizedBy item
+	memory := false
+	for _, op := range stmt.operands {
+		memory = memory || op.kind == OPERAND_MEMORY || op.kind == OPERAND_SYMBOL ||
+			(op.kind == OPERAND_BOUND && op.bound < len(src.operands) && src.operands[op.bound].memory)
+		opSize, written := operandSize(op, src)
+		if opSize == 0 || !instruction.SameSize {
+			continue
+		}
+		if size == 0 {
+			size, sizedBy = opSize, written
+			continue
+		}
+		if opSize == size {
+			continue
+		}
+		what := "register"
+		if op.kind == OPERAND_BOUND {
+			what = "operand"
+		}
+		diagnostic := v.diagnostics.Error(diagnostics.E_OPERAND_SIZE, src.span(written.start, written.end),
+			"`%s` is %s %d bit %s but `%s` works on %d bit operands", written.text, article(opSize), opSize, what, mnemonic, size).
+			WithLabel(fmt.Sprintf("expected %s %d bit %s", article(size), size, what))
+		if sizedBy.text != "" {
+			diagnostic.WithSecondary(src.span(sizedBy.start, sizedBy.end),
+				fmt.Sprintf("operand size set by `%s`", sizedBy.text))
+		}
+		if op.kind == OPERAND_BOUND && op.modifier == 0 && src.operands[op.bound].name != "" {
+			diagnostic.WithNote(fmt.Sprintf("`%s` is bound to `%s`", written.text, src.operands[op.bound].name))
+		}
+	}
+
+	if size == 0 && instruction.SameSize && instruction.Suffixes != "" && memory {
+		forms := make([]string, 0, len(instruction.Suffixes))
+		for _, suffix := range instruction.Suffixes {
+			forms = append(forms, "`"+mnemonic+string(suffix)+"`")
+		}
+		v.diagnostics.Error(diagnostics.E_OPERAND_SIZE, src.span(stmt.mnemonic.start, stmt.mnemonic.end),
+			"`%s` needs a size suffix", mnemonic).
+			WithLabel("operand size is ambiguous without a register operand").
+			WithNote(fmt.Sprintf("write %s", joinOr(forms)))
+		return
+	}
+
+	// an immediate is at most 32 bits, sign extended for 64 bit operands
+	if size == 0 || size == 64 {
+		return
+	}
+	for _, op := range stmt.operands {
+		if op.kind != OPERAND_IMMEDIATE {
+			continue
+		}
+		value, err := strconv.ParseInt(strings.TrimPrefix(op.text, "$"), 0, 64)
+		if err != nil && !strings.Contains(err.Error(), "range") {
+			// a symbol, its value is up to the linker
+			continue
+		}
+		if err == nil && value >= -(1<<(size-1)) && value < 1<<size {
+			continue
+		}
+		v.diagnostics.Error(diagnostics.E_OPERAND_SIZE, src.span(op.start, op.end),
+			"immediate `%s` does not fit in %d bits", op.text, size).
+			WithLabel(fmt.Sprintf("`%s` takes %s %d bit immediate", mnemonic, article(size), size))
+	}
+}
+
+// operandSize is the size of an operand that is a general purpose register
+// once assembled, 0 for every other operand
+func operandSize(op operand, src source) (int, item) {
+	switch op.kind {
+	case OPERAND_REGISTER:
+		if register, _ := LookupRegister(strings.TrimPrefix(op.register.text, "%")); register.Class == REG_GENERAL {
+			return register.Size, op.register
+		}
+	case OPERAND_BOUND:
+		if size, ok := modifierSizes[op.modifier]; ok {
+			return size, op.item
+		}
+		if op.bound < len(src.operands) && !src.operands[op.bound].memory {
+			return src.operands[op.bound].size, op.item
+		}
+	}
+	return 0, item{}
+}
+
+// suffixNote tells which size suffixes an instruction may be written with
+func suffixNote(instruction Instruction) string {
+	if instruction.Suffixes == "" {
+		return fmt.Sprintf("`%s` is written without a size suffix", instruction.Name)
+	}
+	suffixes := make([]string, 0, len(instruction.Suffixes))
+	for _, suffix := range instruction.Suffixes {
+		suffixes = append(suffixes, "`"+string(suffix)+"`")
+	}
+	return fmt.Sprintf("`%s` only takes the %s suffix", instruction.Name, joinOr(suffixes))
+}
+
+// operandCount describes how many operands an instruction takes
+func operandCount(instruction Instruction) string {
+	switch {
+	case instruction.Max == 0:
+		return "no operands"
+	case instruction.Min == instruction.Max:
+		return fmt.Sprintf("%d %s", instruction.Max, plural(instruction.Max, "operand", "operands"))
+	default:
+		return fmt.Sprintf("%d to %d operands", instruction.Min, instruction.Max)
+	}
+}
+
+// plural picks the singular or plural form of a word for a count
+func plural(count int, singular, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
+
+// article picks a or an for a number of bits
+func article(bits int) string {
+	if bits == 8 || bits == 80 {
+		return "an"
+	}
+	return "a"
+}
+
+// joinOr lists words as `a`, `b` or `c`
+func joinOr(words []string) string {
+	if len(words) == 1 {
+		return words[0]
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " or " + words[len(words)-1]
+}
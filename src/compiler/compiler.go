@@ -1,27 +1,117 @@
 package compiler
 
 import (
+	"fmt"
+	"os"
+
+	"github.com/CFdefense/compiler/src/asm"
+	"github.com/CFdefense/compiler/src/constant"
 	debugger "github.com/CFdefense/compiler/src/debug"
+	"github.com/CFdefense/compiler/src/diagnostics"
 	"github.com/CFdefense/compiler/src/lexer"
+	"github.com/CFdefense/compiler/src/parser"
 )
 
 // main compiler struct to hold all compiler components
 // TODO add the rest of the components
 type Compiler struct {
-	lexer *lexer.Lexer
-	debug *debugger.Debug
+	lexer       *lexer.Lexer
+	parser      *parser.Parser
+	evaluator   *constant.Evaluator
+	validator   *asm.Validator
+	program     *parser.Program
+	trees       map[string]*parser.SyntaxNode // concrete syntax tree of each file
+	diagnostics *diagnostics.Reporter
+	debug       *debugger.Debug
 }
 
 // compiler constructor
 // can have a debug mode for verbose outputs
+// every phase reports into one shared diagnostics reporter
 func InitializeCompiler(debug bool) *Compiler {
-	return &Compiler{
-		lexer: lexer.InitializeLexer(debug),
-		debug: debugger.InitializeDebugger("CMP", debug),
+	c := &Compiler{
+		lexer:       lexer.InitializeLexer(debug),
+		parser:      parser.InitializeParser(debug),
+		evaluator:   constant.InitializeEvaluator(debug),
+		validator:   asm.InitializeValidator(debug),
+		diagnostics: diagnostics.InitializeReporter(debug),
+		debug:       debugger.InitializeDebugger("CMP", debug),
 	}
+	c.lexer.SetReporter(c.diagnostics)
+	c.parser.SetReporter(c.diagnostics)
+	c.evaluator.SetReporter(c.diagnostics)
+	c.validator.SetReporter(c.diagnostics)
+	return c
+}
+
+// function to allow identifiers with unicode letters, they are ASCII only by default
+func (c *Compiler) SetUnicodeIdentifiers(enabled bool) {
+	c.lexer.SetUnicodeIdentifiers(enabled)
+}
+
+// function to read a // that starts a line as a comment, with a warning to change it to #
+func (c *Compiler) SetLegacyComments(enabled bool) {
+	c.lexer.SetLegacyComments(enabled)
+}
+
+// function to set what source columns count in tokens and diagnostics, runes by default
+func (c *Compiler) SetColumnUnit(unit diagnostics.ColumnUnit) {
+	c.lexer.SetColumnUnit(unit)
 }
 
 // function to initiate lexical
 func (c *Compiler) BeginLexicalAnalysis(path string) {
 	c.lexer.LexicalAnalysis(path)
 }
+
+// function to initiate syntax analysis
+// each file is parsed on its own so no declaration can run across files
+// the declarations are joined into one program in file order
+func (c *Compiler) BeginSyntaxAnalysis() {
+	program := &parser.Program{}
+	c.trees = make(map[string]*parser.SyntaxNode)
+	for _, file := range c.lexer.GetFiles() {
+		c.parser.SetEndTrivia(c.lexer.GetEndTrivia(file))
+		c.parser.SyntaxAnalysis(c.lexer.GetFileTokenStream(file))
+		program.Declarations = append(program.Declarations, c.parser.GetAST().Declarations...)
+		c.trees[file] = c.parser.GetCST()
+	}
+	c.program = program
+	c.debug.DebugLog(program.String(), false)
+}
+
+// function to get the concrete syntax tree of a source file, nil if it was not parsed
+// its text is the source of the file, for tools that rewrite it
+func (c *Compiler) GetCST(file string) *parser.SyntaxNode {
+	return c.trees[file]
+}
+
+// function to initiate constant evaluation
+// folds the constants of the whole program and checks what they are narrowed into
+func (c *Compiler) BeginConstantEvaluation() {
+	c.evaluator.ConstantEvaluation(c.program)
+}
+
+// function to initiate inline assembly validation
+// checks every asm statement against the x86-64 instruction table
+func (c *Compiler) BeginAsmValidation() {
+	c.validator.AsmValidation(c.program)
+}
+
+// function to print every diagnostic reported so far to stderr
+// returns true if any of them were errors
+func (c *Compiler) EmitDiagnostics() bool {
+	if len(c.diagnostics.GetDiagnostics()) == 0 {
+		return false
+	}
+	fmt.Fprintln(os.Stderr, c.diagnostics.RenderAll())
+
+	errors := c.diagnostics.ErrorCount()
+	switch {
+	case errors == 1:
+		fmt.Fprintln(os.Stderr, "error: aborting due to 1 previous error")
+	case errors > 1:
+		fmt.Fprintf(os.Stderr, "error: aborting due to %d previous errors\n", errors)
+	}
+	return errors > 0
+}
@@ -0,0 +1,695 @@
+package constant
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	debugger "github.com/CFdefense/compiler/src/debug"
+	"github.com/CFdefense/compiler/src/diagnostics"
+	"github.com/CFdefense/compiler/src/lexer"
+	"github.com/CFdefense/compiler/src/parser"
+)
+
+// values wider than this are reported instead of folded
+// so a chain of constants squaring each other cannot exhaust memory
+const MAX_CONSTANT_BITS = 1 << 16
+
+// why an expression did not fold
+var (
+	errNotConstant = errors.New("not a constant expression")
+	errReported    = errors.New("constant expression error already reported")
+)
+
+// Evaluator context object
+// folds constant expressions exactly on math/big and
+// checks them against the types they are narrowed into
+type Evaluator struct {
+	constants   map[string]Value
+	failed      map[string]bool // constants whose error was reported, references to them are not reported again
+	debug       *debugger.Debug
+	diagnostics *diagnostics.Reporter
+}
+
+// Evaluator object constructor
+func InitializeEvaluator(debug bool) *Evaluator {
+	return &Evaluator{
+		constants:   make(map[string]Value),
+		failed:      make(map[string]bool),
+		debug:       debugger.InitializeDebugger("CON", debug),
+		diagnostics: diagnostics.InitializeReporter(debug),
+	}
+}
+
+// Function responsible for all things constant evaluation
+// folds the constant declarations in order so later ones may use earlier ones,
+// then checks every constant variable initializer against its type
+func (e *Evaluator) ConstantEvaluation(program *parser.Program) {
+	e.debug.DebugLog(fmt.Sprintf("constant: evaluating %d declarations", len(program.Declarations)), false)
+	for _, decl := range program.Declarations {
+		if constDecl, ok := decl.(*parser.ConstDecl); ok {
+			e.evaluateConst(constDecl)
+		}
+	}
+	for _, decl := range program.Declarations {
+		if function, ok := decl.(*parser.FunctionDecl); ok {
+			e.checkStatement(function.Body)
+		}
+	}
+}
+
+// Function to fold a single expression
+// returns false if it is not constant or an error was reported
+func (e *Evaluator) Evaluate(expr parser.Expression) (Value, bool) {
+	value, err := e.eval(expr)
+	return value, err == nil
+}
+
+// Function to reset an evaluator
+// mostly used in repeated test executions
+func (e *Evaluator) ResetEvaluator() {
+	e.constants = make(map[string]Value)
+	e.failed = make(map[string]bool)
+	e.diagnostics.ResetReporter()
+}
+
+// Function to get the value of a folded constant declaration
+func (e *Evaluator) GetConstant(name string) (Value, bool) {
+	value, ok := e.constants[name]
+	return value, ok
+}
+
+// Function to get every folded constant keyed by name
+func (e *Evaluator) GetConstants() map[string]Value {
+	return e.constants
+}
+
+// Function to get the diagnostics reported while evaluating
+func (e *Evaluator) GetReporter() *diagnostics.Reporter {
+	return e.diagnostics
+}
+
+// Function to share a diagnostics reporter with the other compiler phases
+func (e *Evaluator) SetReporter(reporter *diagnostics.Reporter) {
+	e.diagnostics = reporter
+}
+
+// evaluateConst folds a constant declaration and narrows it into its type
+// a constant that fails is recorded so only its own error is reported
+func (e *Evaluator) evaluateConst(decl *parser.ConstDecl) {
+	value, err := e.eval(decl.Value)
+	if errors.Is(err, errNotConstant) {
+		e.diagnostics.Error(diagnostics.E_NOT_CONSTANT, exprSpan(decl.Value),
+			"value of constant `%s` is not a constant expression", decl.Name).
+			WithLabel("cannot be computed at compile time")
+	}
+	if err == nil {
+		value, err = e.narrow(value, decl.Type, decl.Value)
+	}
+	if err != nil {
+		e.failed[decl.Name] = true
+		return
+	}
+	e.constants[decl.Name] = value
+	e.debug.DebugLog(fmt.Sprintf("constant: %s = %s (%s)", decl.Name, value, value.TypeName()), false)
+}
+
+// checkStatement looks for variable declarations with constant initializers
+func (e *Evaluator) checkStatement(stmt parser.Statement) {
+	switch stmt := stmt.(type) {
+	case *parser.Block:
+		for _, inner := range stmt.Statements {
+			e.checkStatement(inner)
+		}
+	case *parser.VarDecl:
+		e.checkVarDecl(stmt)
+	case *parser.IfStatement:
+		e.checkStatement(stmt.Then)
+		if stmt.Else != nil {
+			e.checkStatement(stmt.Else)
+		}
+	case *parser.WhileStatement:
+		e.checkStatement(stmt.Body)
+	case *parser.DoWhileStatement:
+		e.checkStatement(stmt.Body)
+	case *parser.ForStatement:
+		if stmt.Init != nil {
+			e.checkStatement(stmt.Init)
+		}
+		e.checkStatement(stmt.Body)
+	case *parser.LabeledStatement:
+		e.checkStatement(stmt.Body)
+	case *parser.MatchStatement:
+		for _, arm := range stmt.Arms {
+			if body, ok := arm.Body.(parser.Statement); ok {
+				e.checkStatement(body)
+			}
+		}
+	}
+}
+
+// checkVarDecl narrows every constant initializer of a declaration into its type
+// pointers and arrays are left to later phases
+func (e *Evaluator) checkVarDecl(decl *parser.VarDecl) {
+	for _, declarator := range decl.Declarators {
+		if declarator.Init == nil || declarator.Pointers > 0 || declarator.IsFuncPointer || len(declarator.ArrayDims) > 0 {
+			continue
+		}
+		if value, err := e.eval(declarator.Init); err == nil {
+			e.narrow(value, decl.Type, declarator.Init)
+		}
+	}
+}
+
+// narrow converts a constant into the declared type of whatever holds it
+// reporting values the type cannot hold, a type it does not know is left alone
+func (e *Evaluator) narrow(value Value, typeNode *parser.TypeNode, expr parser.Expression) (Value, error) {
+	if typeNode.Pointers > 0 {
+		return value, nil
+	}
+	span := exprSpan(expr)
+	name := typeNode.Name
+
+	if intType, ok := LookupIntType(name); ok {
+		switch value.kind {
+		case KIND_BOOL:
+			return e.cannotUse(value, name, span)
+		case KIND_FLOAT:
+			if !value.float.IsInt() {
+				e.diagnostics.Error(diagnostics.E_CONSTANT_TRUNCATED, span,
+					"constant %s truncated to integer `%s`", value, name).
+					WithLabel("has a fraction").
+					WithNote(fmt.Sprintf("cast with `(%s)` to drop the fraction", name))
+				return Value{}, errReported
+			}
+			value = intValue(new(big.Int).Set(value.float.Num()), nil)
+		}
+		if value.intType != nil && value.intType.Name != intType.Name {
+			return e.cannotUse(value, name, span)
+		}
+		value.intType = &intType
+		return value, e.checkRange(value, span)
+	}
+
+	switch name {
+	case "float":
+		if value.kind == KIND_BOOL || value.intType != nil {
+			return e.cannotUse(value, name, span)
+		}
+		if value.kind == KIND_INT {
+			value = floatValue(new(big.Rat).SetInt(value.integer))
+		}
+		if f, _ := value.float.Float64(); math.IsInf(f, 0) {
+			e.diagnostics.Error(diagnostics.E_CONSTANT_OVERFLOW, span, "constant overflows `float`").
+				WithLabel("rounds to infinity as a 64 bit float")
+			return Value{}, errReported
+		}
+	case "bool":
+		if value.kind != KIND_BOOL {
+			return e.cannotUse(value, name, span)
+		}
+	}
+	return value, nil
+}
+
+// cannotUse reports a constant whose type does not convert to the declared one
+func (e *Evaluator) cannotUse(value Value, name string, span diagnostics.Span) (Value, error) {
+	diagnostic := e.diagnostics.Error(diagnostics.E_INVALID_CONSTANT_OP, span,
+		"cannot use %s constant as `%s`", describeType(value), name).
+		WithLabel(fmt.Sprintf("expected `%s`", name))
+	if value.kind != KIND_BOOL && name != "bool" {
+		diagnostic.WithNote(fmt.Sprintf("cast with `(%s)` to convert it", name))
+	}
+	return Value{}, errReported
+}
+
+// checkRange reports a value that does not fit in its integer type
+// or that has grown past MAX_CONSTANT_BITS
+func (e *Evaluator) checkRange(value Value, span diagnostics.Span) error {
+	size := 0
+	switch value.kind {
+	case KIND_INT:
+		size = value.integer.BitLen()
+	case KIND_FLOAT:
+		size = value.float.Num().BitLen() + value.float.Denom().BitLen()
+	}
+	if size > MAX_CONSTANT_BITS {
+		e.diagnostics.Error(diagnostics.E_CONSTANT_OVERFLOW, span, "constant is too large").
+			WithLabel(fmt.Sprintf("needs more than %d bits", MAX_CONSTANT_BITS))
+		return errReported
+	}
+
+	intType := value.intType
+	if value.kind != KIND_INT || intType == nil || intType.Contains(value.integer) {
+		return nil
+	}
+	e.diagnostics.Error(diagnostics.E_CONSTANT_OVERFLOW, span,
+		"constant %s overflows `%s`", value, intType.Name).
+		WithLabel(fmt.Sprintf("`%s` holds values from %s to %s", intType.Name, intType.Min(), intType.Max())).
+		WithNote(fmt.Sprintf("cast with `(%s)` to keep the low %d bits", intType.Name, intType.Bits))
+	return errReported
+}
+
+// eval folds an expression
+// anything that reads memory, calls or assigns is not constant
+func (e *Evaluator) eval(expr parser.Expression) (Value, error) {
+	switch expr := expr.(type) {
+	case *parser.Literal:
+		value, err := e.literal(expr)
+		if err != nil {
+			return value, err
+		}
+		return value, e.checkRange(value, exprSpan(expr))
+	case *parser.Identifier:
+		if value, ok := e.constants[expr.Name]; ok {
+			return value, nil
+		}
+		if e.failed[expr.Name] {
+			return Value{}, errReported
+		}
+	case *parser.UnaryExpr:
+		return e.unary(expr)
+	case *parser.BinaryExpr:
+		return e.binary(expr)
+	case *parser.TernaryExpr:
+		return e.ternary(expr)
+	case *parser.CastExpr:
+		return e.cast(expr)
+	}
+	return Value{}, errNotConstant
+}
+
+// literal gives the value of a literal token without checking its range
+// a number with an integer suffix has that type, a char is its code point
+func (e *Evaluator) literal(lit *parser.Literal) (Value, error) {
+	if lit.Kind == lexer.T_ERROR {
+		// out of range for its suffix, which the lexer reported
+		return Value{}, errReported
+	}
+	literal := lit.Token.GetValue()
+	if literal == nil {
+		return Value{}, errNotConstant
+	}
+	switch literal.GetKind() {
+	case lexer.LITERAL_INT:
+		value := intValue(literal.GetInt(), nil)
+		if intType, ok := LookupIntType(literal.GetSuffix()); ok {
+			value.intType = &intType
+		}
+		return value, nil
+	case lexer.LITERAL_FLOAT:
+		return floatValue(literal.GetFloat()), nil
+	case lexer.LITERAL_BOOL:
+		return boolValue(literal.GetBool()), nil
+	case lexer.LITERAL_CHAR:
+		return intValue(big.NewInt(int64(literal.GetChar())), nil), nil
+	}
+	return Value{}, errNotConstant
+}
+
+// unary folds - + ~ and !
+func (e *Evaluator) unary(expr *parser.UnaryExpr) (Value, error) {
+	switch expr.Op {
+	case "-", "+", "~", "!":
+	default:
+		return Value{}, errNotConstant
+	}
+	if expr.Postfix {
+		return Value{}, errNotConstant
+	}
+
+	var operand Value
+	var err error
+	if lit, ok := expr.Operand.(*parser.Literal); ok && expr.Op == "-" {
+		// -128i8 fits even though 128i8 alone does not
+		operand, err = e.literal(lit)
+	} else {
+		operand, err = e.eval(expr.Operand)
+	}
+	if err != nil {
+		return Value{}, err
+	}
+
+	var result Value
+	switch {
+	case expr.Op == "!" && operand.kind == KIND_BOOL:
+		result = boolValue(!operand.boolean)
+	case expr.Op == "~" && operand.kind == KIND_INT:
+		if operand.intType != nil && !operand.intType.Signed {
+			result = intValue(new(big.Int).Xor(operand.integer, operand.intType.Max()), operand.intType)
+		} else {
+			result = intValue(new(big.Int).Not(operand.integer), operand.intType)
+		}
+	case expr.Op == "-" && operand.kind == KIND_INT:
+		result = intValue(new(big.Int).Neg(operand.integer), operand.intType)
+	case expr.Op == "-" && operand.kind == KIND_FLOAT:
+		result = floatValue(new(big.Rat).Neg(operand.float))
+	case expr.Op == "+" && operand.kind != KIND_BOOL:
+		result = operand
+	default:
+		e.diagnostics.Error(diagnostics.E_INVALID_CONSTANT_OP, exprSpan(expr),
+			"cannot apply `%s` to %s constant", expr.Op, describeType(operand)).
+			WithLabel(fmt.Sprintf("operand is %s", describeType(operand)))
+		return Value{}, errReported
+	}
+	return result, e.checkRange(result, exprSpan(expr))
+}
+
+// binary folds every binary operator but the comma
+func (e *Evaluator) binary(expr *parser.BinaryExpr) (Value, error) {
+	if expr.Op == "," {
+		return Value{}, errNotConstant
+	}
+	left, err := e.eval(expr.Left)
+	if err != nil {
+		return Value{}, err
+	}
+	right, err := e.eval(expr.Right)
+	if err != nil {
+		return Value{}, err
+	}
+
+	switch expr.Op {
+	case "<<", ">>":
+		return e.shift(expr, left, right)
+	case "&&", "||":
+		if left.kind != KIND_BOOL || right.kind != KIND_BOOL {
+			return e.invalidOperands(expr, left, right)
+		}
+		if expr.Op == "&&" {
+			return boolValue(left.boolean && right.boolean), nil
+		}
+		return boolValue(left.boolean || right.boolean), nil
+	}
+
+	if left, right, err = e.unify(expr, left, right); err != nil {
+		return Value{}, err
+	}
+	switch left.kind {
+	case KIND_BOOL:
+		switch expr.Op {
+		case "==":
+			return boolValue(left.boolean == right.boolean), nil
+		case "!=":
+			return boolValue(left.boolean != right.boolean), nil
+		}
+		return e.invalidOperands(expr, left, right)
+	case KIND_FLOAT:
+		return e.floatBinary(expr, left, right)
+	}
+	return e.intBinary(expr, left, right)
+}
+
+// unify brings both operands to one type
+// an untyped integer takes the type of the other side and turns into a float next to one
+func (e *Evaluator) unify(expr *parser.BinaryExpr, left, right Value) (Value, Value, error) {
+	switch {
+	case left.kind == KIND_BOOL || right.kind == KIND_BOOL:
+		if left.kind != right.kind {
+			return left, right, e.mismatched(expr, left, right)
+		}
+	case left.kind == KIND_FLOAT || right.kind == KIND_FLOAT:
+		if left.intType != nil || right.intType != nil {
+			return left, right, e.mismatched(expr, left, right)
+		}
+		if left.kind == KIND_INT {
+			left = floatValue(new(big.Rat).SetInt(left.integer))
+		}
+		if right.kind == KIND_INT {
+			right = floatValue(new(big.Rat).SetInt(right.integer))
+		}
+	case left.intType == nil:
+		left.intType = right.intType
+	case right.intType == nil:
+		right.intType = left.intType
+	case left.intType.Name != right.intType.Name:
+		return left, right, e.mismatched(expr, left, right)
+	}
+	return left, right, nil
+}
+
+// intBinary folds an operator on two integers of one type
+// / truncates toward zero, // rounds toward negative infinity and % takes the sign of the dividend
+func (e *Evaluator) intBinary(expr *parser.BinaryExpr, left, right Value) (Value, error) {
+	x, y := left.integer, right.integer
+	result := new(big.Int)
+	switch expr.Op {
+	case "+":
+		result.Add(x, y)
+	case "-":
+		result.Sub(x, y)
+	case "*":
+		result.Mul(x, y)
+	case "/", "//", "%":
+		if y.Sign() == 0 {
+			return Value{}, e.divisionByZero(expr)
+		}
+		remainder := new(big.Int)
+		result.QuoRem(x, y, remainder)
+		switch {
+		case expr.Op == "%":
+			result = remainder
+		case expr.Op == "//" && remainder.Sign() != 0 && (remainder.Sign() < 0) != (y.Sign() < 0):
+			result.Sub(result, big.NewInt(1))
+		}
+	case "&":
+		result.And(x, y)
+	case "|":
+		result.Or(x, y)
+	case "^":
+		result.Xor(x, y)
+	default:
+		return compare(expr.Op, x.Cmp(y))
+	}
+	value := intValue(result, left.intType)
+	return value, e.checkRange(value, exprSpan(expr))
+}
+
+// floatBinary folds an operator on two floats, exactly as fractions
+func (e *Evaluator) floatBinary(expr *parser.BinaryExpr, left, right Value) (Value, error) {
+	x, y := left.float, right.float
+	result := new(big.Rat)
+	switch expr.Op {
+	case "+":
+		result.Add(x, y)
+	case "-":
+		result.Sub(x, y)
+	case "*":
+		result.Mul(x, y)
+	case "/", "//":
+		if y.Sign() == 0 {
+			return Value{}, e.divisionByZero(expr)
+		}
+		result.Quo(x, y)
+		if expr.Op == "//" {
+			// the denominator is always positive so Euclidean division floors
+			result.SetInt(new(big.Int).Div(result.Num(), result.Denom()))
+		}
+	case "%", "&", "|", "^":
+		return e.invalidOperands(expr, left, right)
+	default:
+		return compare(expr.Op, x.Cmp(y))
+	}
+	value := floatValue(result)
+	return value, e.checkRange(value, exprSpan(expr))
+}
+
+// compare turns the result of a Cmp into the value of a comparison operator
+func compare(op string, cmp int) (Value, error) {
+	switch op {
+	case "==":
+		return boolValue(cmp == 0), nil
+	case "!=":
+		return boolValue(cmp != 0), nil
+	case "<":
+		return boolValue(cmp < 0), nil
+	case ">":
+		return boolValue(cmp > 0), nil
+	case "<=":
+		return boolValue(cmp <= 0), nil
+	case ">=":
+		return boolValue(cmp >= 0), nil
+	}
+	return Value{}, errNotConstant
+}
+
+// shift folds << and >>, the result has the type of the left operand
+// >> of a negative number keeps its sign
+func (e *Evaluator) shift(expr *parser.BinaryExpr, left, right Value) (Value, error) {
+	if left.kind != KIND_INT || right.kind != KIND_INT {
+		return e.invalidOperands(expr, left, right)
+	}
+	count := right.integer
+	if count.Sign() < 0 {
+		e.diagnostics.Error(diagnostics.E_INVALID_CONSTANT_OP, exprSpan(expr.Right),
+			"negative shift count %s", count).
+			WithLabel("must be zero or more")
+		return Value{}, errReported
+	}
+	if !count.IsInt64() || count.Int64() > MAX_CONSTANT_BITS {
+		e.diagnostics.Error(diagnostics.E_INVALID_CONSTANT_OP, exprSpan(expr.Right),
+			"shift count %s is too large", count).
+			WithLabel(fmt.Sprintf("must be at most %d", MAX_CONSTANT_BITS))
+		return Value{}, errReported
+	}
+
+	result := new(big.Int)
+	if expr.Op == "<<" {
+		result.Lsh(left.integer, uint(count.Int64()))
+	} else {
+		result.Rsh(left.integer, uint(count.Int64()))
+	}
+	value := intValue(result, left.intType)
+	return value, e.checkRange(value, exprSpan(expr))
+}
+
+// ternary folds cond ? then : else, both branches must be constant
+func (e *Evaluator) ternary(expr *parser.TernaryExpr) (Value, error) {
+	condition, err := e.eval(expr.Condition)
+	if err != nil {
+		return Value{}, err
+	}
+	then, err := e.eval(expr.Then)
+	if err != nil {
+		return Value{}, err
+	}
+	otherwise, err := e.eval(expr.Else)
+	if err != nil {
+		return Value{}, err
+	}
+	if condition.kind != KIND_BOOL {
+		e.diagnostics.Error(diagnostics.E_INVALID_CONSTANT_OP, exprSpan(expr.Condition),
+			"condition must be a bool constant, found %s", describeType(condition)).
+			WithLabel("not a bool")
+		return Value{}, errReported
+	}
+	if condition.boolean {
+		return then, nil
+	}
+	return otherwise, nil
+}
+
+// cast converts a constant into a type without reporting overflow
+// an integer keeps its low bits and a float drops its fraction
+func (e *Evaluator) cast(expr *parser.CastExpr) (Value, error) {
+	if expr.Type.Pointers > 0 {
+		return Value{}, errNotConstant
+	}
+	name := expr.Type.Name
+	intType, isInt := LookupIntType(name)
+	if !isInt && name != "float" && name != "bool" {
+		return Value{}, errNotConstant
+	}
+	operand, err := e.eval(expr.Operand)
+	if err != nil {
+		return Value{}, err
+	}
+
+	switch {
+	case isInt && operand.kind == KIND_INT:
+		return intValue(intType.Wrap(operand.integer), &intType), nil
+	case isInt && operand.kind == KIND_FLOAT:
+		integer := new(big.Int).Quo(operand.float.Num(), operand.float.Denom())
+		return intValue(intType.Wrap(integer), &intType), nil
+	case name == "float" && operand.kind == KIND_INT:
+		return floatValue(new(big.Rat).SetInt(operand.integer)), nil
+	case name == "float" && operand.kind == KIND_FLOAT, name == "bool" && operand.kind == KIND_BOOL:
+		return operand, nil
+	}
+	e.diagnostics.Error(diagnostics.E_INVALID_CONSTANT_OP, exprSpan(expr),
+		"cannot cast %s constant to `%s`", describeType(operand), name).
+		WithLabel("invalid cast")
+	return Value{}, errReported
+}
+
+// invalidOperands reports an operator the types of its operands do not allow
+func (e *Evaluator) invalidOperands(expr *parser.BinaryExpr, left, right Value) (Value, error) {
+	e.diagnostics.Error(diagnostics.E_INVALID_CONSTANT_OP, tokenSpan(expr.Token),
+		"cannot apply `%s` to %s and %s constants", expr.Op, describeType(left), describeType(right)).
+		WithLabel("invalid operator for these types")
+	return Value{}, errReported
+}
+
+// mismatched reports operands of two different types
+func (e *Evaluator) mismatched(expr *parser.BinaryExpr, left, right Value) error {
+	e.diagnostics.Error(diagnostics.E_INVALID_CONSTANT_OP, tokenSpan(expr.Token),
+		"mismatched types %s and %s in constant expression", describeType(left), describeType(right)).
+		WithLabel(fmt.Sprintf("`%s` needs both sides to have one type", expr.Op))
+	return errReported
+}
+
+// divisionByZero reports a division or remainder by a zero constant
+func (e *Evaluator) divisionByZero(expr *parser.BinaryExpr) error {
+	e.diagnostics.Error(diagnostics.E_DIVISION_BY_ZERO, exprSpan(expr.Right),
+		"division by zero in constant expression").
+		WithLabel("this is zero")
+	return errReported
+}
+
+// describeType names the type of a value in a message, `u8`, untyped int, float or bool
+func describeType(value Value) string {
+	if value.kind == KIND_INT && value.intType != nil {
+		return fmt.Sprintf("`%s`", value.intType.Name)
+	}
+	return value.TypeName()
+}
+
+// exprSpan covers an expression from its first token to its last
+// only the first token is covered when it runs over several lines
+func exprSpan(expr parser.Expression) diagnostics.Span {
+	first, last := firstToken(expr), lastToken(expr)
+	span := tokenSpan(first)
+	if last.GetEndRow() == first.GetRow() && last.GetEndCol() > first.GetCol() {
+		span.Length = last.GetEndCol() - first.GetCol()
+	}
+	return span
+}
+
+// tokenSpan builds a diagnostic span covering a token
+func tokenSpan(token lexer.Token) diagnostics.Span {
+	return diagnostics.Span{
+		File:   token.GetFile(),
+		Row:    token.GetRow(),
+		Col:    token.GetCol(),
+		Length: max(token.GetEndCol()-token.GetCol(), 1),
+	}
+}
+
+// firstToken finds the leftmost token of an expression
+func firstToken(expr parser.Expression) lexer.Token {
+	switch expr := expr.(type) {
+	case *parser.BinaryExpr:
+		return firstToken(expr.Left)
+	case *parser.TernaryExpr:
+		return firstToken(expr.Condition)
+	case *parser.CallExpr:
+		return firstToken(expr.Callee)
+	case *parser.IndexExpr:
+		return firstToken(expr.Array)
+	case *parser.MemberExpr:
+		return firstToken(expr.Object)
+	case *parser.UnaryExpr:
+		if expr.Postfix {
+			return firstToken(expr.Operand)
+		}
+	}
+	return expr.GetToken()
+}
+
+// lastToken finds the rightmost token of an expression that has one
+func lastToken(expr parser.Expression) lexer.Token {
+	switch expr := expr.(type) {
+	case *parser.BinaryExpr:
+		return lastToken(expr.Right)
+	case *parser.TernaryExpr:
+		return lastToken(expr.Else)
+	case *parser.UnaryExpr:
+		if !expr.Postfix {
+			return lastToken(expr.Operand)
+		}
+	case *parser.CastExpr:
+		return lastToken(expr.Operand)
+	}
+	return expr.GetToken()
+}
@@ -0,0 +1,174 @@
+package constant
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// IntType is an integer type a constant can be narrowed into
+// Bits is 0 for big, which holds an integer of any width
+type IntType struct {
+	Name   string
+	Bits   int
+	Signed bool
+}
+
+// integer types by keyword, int is a 64 bit signed integer
+var intTypes = map[string]IntType{
+	"int":  {Name: "int", Bits: 64, Signed: true},
+	"i8":   {Name: "i8", Bits: 8, Signed: true},
+	"i16":  {Name: "i16", Bits: 16, Signed: true},
+	"i32":  {Name: "i32", Bits: 32, Signed: true},
+	"i64":  {Name: "i64", Bits: 64, Signed: true},
+	"i128": {Name: "i128", Bits: 128, Signed: true},
+	"u8":   {Name: "u8", Bits: 8},
+	"u16":  {Name: "u16", Bits: 16},
+	"u32":  {Name: "u32", Bits: 32},
+	"u64":  {Name: "u64", Bits: 64},
+	"u128": {Name: "u128", Bits: 128},
+	"big":  {Name: "big", Signed: true},
+}
+
+// LookupIntType finds the integer type named by a type keyword or literal suffix
+func LookupIntType(name string) (IntType, bool) {
+	intType, ok := intTypes[name]
+	return intType, ok
+}
+
+// Min is the smallest value of the type, nil for big
+func (t IntType) Min() *big.Int {
+	if t.Bits == 0 {
+		return nil
+	}
+	if !t.Signed {
+		return new(big.Int)
+	}
+	return new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), uint(t.Bits-1)))
+}
+
+// Max is the largest value of the type, nil for big
+func (t IntType) Max() *big.Int {
+	if t.Bits == 0 {
+		return nil
+	}
+	bits := t.Bits
+	if t.Signed {
+		bits--
+	}
+	max := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	return max.Sub(max, big.NewInt(1))
+}
+
+// Contains checks if a value fits in the type
+func (t IntType) Contains(value *big.Int) bool {
+	if t.Bits == 0 {
+		return true
+	}
+	return value.Cmp(t.Min()) >= 0 && value.Cmp(t.Max()) <= 0
+}
+
+// Wrap keeps the low bits of a value the way a cast into the type does
+// a signed type reads the top kept bit as the sign
+func (t IntType) Wrap(value *big.Int) *big.Int {
+	if t.Bits == 0 {
+		return new(big.Int).Set(value)
+	}
+	modulus := new(big.Int).Lsh(big.NewInt(1), uint(t.Bits))
+	wrapped := new(big.Int).Mod(value, modulus)
+	if t.Signed && wrapped.Cmp(t.Max()) > 0 {
+		wrapped.Sub(wrapped, modulus)
+	}
+	return wrapped
+}
+
+// What kind of value a constant holds
+type Kind int
+
+const (
+	KIND_INT Kind = iota
+	KIND_FLOAT
+	KIND_BOOL
+)
+
+// Value is the exact result of folding a constant expression
+// an integer without a type stays exact until it is narrowed into one
+type Value struct {
+	kind    Kind
+	integer *big.Int
+	float   *big.Rat
+	boolean bool
+	intType *IntType // integer type of the value, nil while untyped
+}
+
+func intValue(integer *big.Int, intType *IntType) Value {
+	return Value{kind: KIND_INT, integer: integer, intType: intType}
+}
+
+func floatValue(float *big.Rat) Value {
+	return Value{kind: KIND_FLOAT, float: float}
+}
+
+func boolValue(boolean bool) Value {
+	return Value{kind: KIND_BOOL, boolean: boolean}
+}
+
+func (v Value) GetKind() Kind {
+	return v.kind
+}
+
+// Function to get the value of an integer constant
+// returns a copy so the constant cannot be changed through it
+func (v Value) GetInt() *big.Int {
+	if v.integer == nil {
+		return nil
+	}
+	return new(big.Int).Set(v.integer)
+}
+
+// Function to get the exact value of a float constant
+// returns a copy so the constant cannot be changed through it
+func (v Value) GetFloat() *big.Rat {
+	if v.float == nil {
+		return nil
+	}
+	return new(big.Rat).Set(v.float)
+}
+
+func (v Value) GetBool() bool {
+	return v.boolean
+}
+
+// Function to get the integer type of a constant, false while it is untyped
+func (v Value) GetType() (IntType, bool) {
+	if v.intType == nil {
+		return IntType{}, false
+	}
+	return *v.intType, true
+}
+
+// TypeName names the type of a value for messages and tests
+func (v Value) TypeName() string {
+	switch {
+	case v.kind == KIND_BOOL:
+		return "bool"
+	case v.kind == KIND_FLOAT:
+		return "float"
+	case v.intType != nil:
+		return v.intType.Name
+	default:
+		return "untyped int"
+	}
+}
+
+// String shows integers in decimal and floats rounded to 64 bits
+func (v Value) String() string {
+	switch v.kind {
+	case KIND_INT:
+		return v.integer.String()
+	case KIND_FLOAT:
+		f, _ := v.float.Float64()
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	default:
+		return strconv.FormatBool(v.boolean)
+	}
+}
@@ -0,0 +1,49 @@
+package diagnostics
+
+// Error codes shown as error[E0001], warning codes as warning[W0100]
+// lexical errors live in E00xx, syntax errors in E01xx, constant errors in E02xx
+// and inline assembly errors in E03xx
+// warnings use the same ranges as the errors of their phase
+const (
+	// Lexical errors
+	E_UNKNOWN_CHARACTER    = "E0001" // character that starts no token
+	E_INVALID_CHARACTER    = "E0002" // non ASCII or control character outside of a literal
+	E_INVALID_UTF8         = "E0003" // byte sequence that is not valid UTF-8
+	E_INTEGER_TOO_LARGE    = "E0004" // integer literal that does not fit in the type of its suffix
+	E_REPEATED_COMMA       = "E0005" // ,, with nothing between the commas
+	E_UNREADABLE_SOURCE    = "E0006" // source directory or file could not be read
+	E_INVALID_ESCAPE       = "E0007" // escape sequence that is unknown or out of range
+	E_UNTERMINATED_LITERAL = "E0008" // string or character literal missing its closing quote
+	E_INVALID_CHAR_LITERAL = "E0009" // character literal without exactly one character
+	E_INVALID_NUMBER       = "E0010" // misplaced digit separator or a suffix the number cannot have
+	E_FLOAT_TOO_LARGE      = "E0011" // float literal that rounds to infinity or has a huge exponent
+	E_NON_ASCII_BYTE       = "E0012" // character outside of ASCII in a byte string or byte literal
+	E_UNTERMINATED_COMMENT = "E0013" // block comment missing one of its closing */
+
+	// Syntax errors
+	E_EXPECTED_TOKEN = "E0100" // the grammar required something else here
+
+	// Constant evaluation errors
+	E_CONSTANT_OVERFLOW   = "E0200" // constant that does not fit in the type it is narrowed into
+	E_DIVISION_BY_ZERO    = "E0201" // constant division or remainder by zero
+	E_INVALID_CONSTANT_OP = "E0202" // operator or conversion the constant's type does not allow
+	E_CONSTANT_TRUNCATED  = "E0203" // float constant with a fraction narrowed into an integer type
+	E_NOT_CONSTANT        = "E0204" // constant declaration whose value cannot be folded
+
+	// Inline assembly errors
+	E_UNKNOWN_INSTRUCTION = "E0300" // mnemonic that is not in the x86-64 instruction table
+	E_UNKNOWN_REGISTER    = "E0301" // register name that x86-64 does not have
+	E_OPERAND_COUNT       = "E0302" // instruction given more or fewer operands than it takes
+	E_OPERAND_SIZE        = "E0303" // operand that does not match the instruction's operand size
+	E_INVALID_OPERAND     = "E0304" // operand the instruction cannot take, such as a bad memory reference
+	E_INVALID_CONSTRAINT  = "E0305" // extended asm constraint that is unknown or not allowed where it is
+	E_INVALID_BINDING     = "E0306" // expression an asm operand cannot bind, by its type or what it is
+	E_IMMUTABLE_BINDING   = "E0307" // variable without mut bound to an asm output
+	E_UNSAFE_REQUIRED     = "E0308" // asm operands in a function that is not @unsafe
+
+	// Lexical warnings
+	W_LEGACY_COMMENT = "W0001" // // comment read at the start of a line with legacy comments on
+
+	// Syntax warnings
+	W_UNUSED_DOC_COMMENT = "W0100" // doc comment with no declaration after it to document
+)
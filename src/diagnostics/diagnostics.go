@@ -0,0 +1,187 @@
+package diagnostics
+
+import (
+	"fmt"
+
+	debugger "github.com/CFdefense/compiler/src/debug"
+)
+
+// How bad a diagnostic is
+type Severity int
+
+const (
+	SEVERITY_ERROR Severity = iota
+	SEVERITY_WARNING
+	SEVERITY_NOTE
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SEVERITY_ERROR:
+		return "error"
+	case SEVERITY_WARNING:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// What the columns and lengths of a Span count
+type ColumnUnit int
+
+const (
+	COLUMN_RUNES ColumnUnit = iota // characters, the default
+	COLUMN_BYTES                   // bytes of the UTF-8 encoding
+)
+
+func (u ColumnUnit) String() string {
+	if u == COLUMN_BYTES {
+		return "bytes"
+	}
+	return "runes"
+}
+
+// Span is a location in a source file
+// Row and Col are 1 based, a Row of 0 means the span has no position
+// Col and Length are counted in the reporter's column unit
+// Length is how many columns to underline
+type Span struct {
+	File   string
+	Row    int
+	Col    int
+	Length int
+	Label  string
+}
+
+// Single reported problem
+// Primary is where the problem is, Secondary spans point at related code
+type Diagnostic struct {
+	Severity  Severity
+	Code      string
+	Message   string
+	Primary   Span
+	Secondary []Span
+	Notes     []string
+}
+
+// Reporter collects diagnostics from every compiler phase
+// phases keep going after reporting so that one run shows every error
+type Reporter struct {
+	diagnostics []*Diagnostic
+	sources     map[string]string
+	columns     ColumnUnit // unit of every span's Col and Length
+	debug       *debugger.Debug
+}
+
+// Reporter object constructor
+func InitializeReporter(debug bool) *Reporter {
+	return &Reporter{
+		diagnostics: []*Diagnostic{},
+		sources:     make(map[string]string),
+		debug:       debugger.InitializeDebugger("DIA", debug),
+	}
+}
+
+// Function to set what span columns count, runes unless set
+// the lexer sets it to the unit its tokens are counted in
+func (r *Reporter) SetColumnUnit(unit ColumnUnit) {
+	r.columns = unit
+}
+
+// Function to get what span columns count
+func (r *Reporter) GetColumnUnit() ColumnUnit {
+	return r.columns
+}
+
+// AddSource registers file content so diagnostics can show source snippets
+func (r *Reporter) AddSource(file string, content string) {
+	r.sources[file] = content
+}
+
+// Report records a diagnostic and returns it so notes and labels can be attached
+func (r *Reporter) Report(severity Severity, code string, span Span, format string, args ...any) *Diagnostic {
+	diagnostic := &Diagnostic{
+		Severity: severity,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+		Primary:  span,
+	}
+	r.diagnostics = append(r.diagnostics, diagnostic)
+	r.debug.DebugLog(diagnostic.Summary(), false)
+	return diagnostic
+}
+
+// Error records an error diagnostic
+func (r *Reporter) Error(code string, span Span, format string, args ...any) *Diagnostic {
+	return r.Report(SEVERITY_ERROR, code, span, format, args...)
+}
+
+// Warning records a warning diagnostic
+func (r *Reporter) Warning(code string, span Span, format string, args ...any) *Diagnostic {
+	return r.Report(SEVERITY_WARNING, code, span, format, args...)
+}
+
+// WithLabel sets the text shown under the primary caret
+func (d *Diagnostic) WithLabel(label string) *Diagnostic {
+	d.Primary.Label = label
+	return d
+}
+
+// WithSecondary points at related code
+func (d *Diagnostic) WithSecondary(span Span, label string) *Diagnostic {
+	span.Label = label
+	d.Secondary = append(d.Secondary, span)
+	return d
+}
+
+// WithNote adds a trailing = note: line
+func (d *Diagnostic) WithNote(note string) *Diagnostic {
+	d.Notes = append(d.Notes, note)
+	return d
+}
+
+// Summary renders a diagnostic on a single line
+// error[E0001] main.txt:3:5: unknown character
+func (d *Diagnostic) Summary() string {
+	return fmt.Sprintf("%s[%s] %s: %s", d.Severity.String(), d.Code, d.Primary.Location(), d.Message)
+}
+
+// Location renders a span as file:row:col
+func (s Span) Location() string {
+	file := s.File
+	if file == "" {
+		file = "<unknown>"
+	}
+	if s.Row == 0 {
+		return file
+	}
+	return fmt.Sprintf("%s:%d:%d", file, s.Row, s.Col)
+}
+
+// Function to get every diagnostic in report order
+func (r *Reporter) GetDiagnostics() []*Diagnostic {
+	return r.diagnostics
+}
+
+// ErrorCount counts the error severity diagnostics
+func (r *Reporter) ErrorCount() int {
+	count := 0
+	for _, diagnostic := range r.diagnostics {
+		if diagnostic.Severity == SEVERITY_ERROR {
+			count++
+		}
+	}
+	return count
+}
+
+// HasErrors reports whether any error has been recorded
+func (r *Reporter) HasErrors() bool {
+	return r.ErrorCount() > 0
+}
+
+// Function to reset a reporter
+// mostly used in repeated test executions
+func (r *Reporter) ResetReporter() {
+	r.diagnostics = []*Diagnostic{}
+	r.sources = make(map[string]string)
+}
@@ -0,0 +1,155 @@
+package diagnostics
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// tabs are expanded so the caret lines up with the source
+const TAB_WIDTH = 4
+
+// Render formats a diagnostic rustc style with the offending source underlined
+//
+//	error[E0001]: unknown character `\`
+//	 --> main.txt:1:3
+//	  |
+//	1 | a \ b
+//	  |   ^ not part of any token
+//	  |
+//	  = note: ...
+func (r *Reporter) Render(d *Diagnostic) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s[%s]: %s\n", d.Severity.String(), d.Code, d.Message))
+
+	// the gutter is as wide as the largest line number shown
+	gutter := len(strconv.Itoa(d.Primary.Row))
+	for _, span := range d.Secondary {
+		gutter = max(gutter, len(strconv.Itoa(span.Row)))
+	}
+	pad := strings.Repeat(" ", gutter)
+
+	// snippets are shown in source order, the primary file first and
+	// other files in the order their first span was added
+	snippets := []snippet{{d.Primary, "^"}}
+	files := map[string]int{d.Primary.File: 0}
+	for _, span := range d.Secondary {
+		snippets = append(snippets, snippet{span, "-"})
+		if _, ok := files[span.File]; !ok {
+			files[span.File] = len(files)
+		}
+	}
+	slices.SortStableFunc(snippets, func(a, b snippet) int {
+		if order := cmp.Compare(files[a.span.File], files[b.span.File]); order != 0 {
+			return order
+		}
+		return cmp.Compare(a.span.Row, b.span.Row)
+	})
+
+	sb.WriteString(fmt.Sprintf("%s--> %s\n", pad, d.Primary.Location()))
+	file := d.Primary.File
+	for _, s := range snippets {
+		if s.span.File != file {
+			file = s.span.File
+			sb.WriteString(fmt.Sprintf("%s::: %s\n", pad, s.span.Location()))
+		}
+		r.renderSnippet(&sb, s.span, s.marker, pad)
+	}
+
+	if len(d.Notes) > 0 {
+		sb.WriteString(pad + " |\n")
+		for _, note := range d.Notes {
+			sb.WriteString(fmt.Sprintf("%s = note: %s\n", pad, note))
+		}
+	}
+	return sb.String()
+}
+
+// a span to show and the character that underlines it
+type snippet struct {
+	span   Span
+	marker string
+}
+
+// RenderAll formats every diagnostic separated by blank lines
+func (r *Reporter) RenderAll() string {
+	rendered := make([]string, 0, len(r.diagnostics))
+	for _, diagnostic := range r.diagnostics {
+		rendered = append(rendered, r.Render(diagnostic))
+	}
+	return strings.Join(rendered, "\n")
+}
+
+// renderSnippet writes the source line of a span with marker characters under it
+// nothing is written when the source or line is not known
+func (r *Reporter) renderSnippet(sb *strings.Builder, span Span, marker string, pad string) {
+	line, ok := r.sourceLine(span.File, span.Row)
+	if !ok {
+		return
+	}
+
+	// byte columns are turned into rune columns to line up with the text
+	if r.columns == COLUMN_BYTES {
+		span = byteSpanToRunes(line, span)
+	}
+
+	// work out the display column of the span start and end
+	runes := []rune(line)
+	var rendered strings.Builder
+	offset := 0
+	start := -1
+	for i, c := range runes {
+		if i == span.Col-1 {
+			start = offset
+		}
+		if c == '\t' {
+			rendered.WriteString(strings.Repeat(" ", TAB_WIDTH))
+			offset += TAB_WIDTH
+		} else {
+			rendered.WriteRune(c)
+			offset++
+		}
+	}
+	if start == -1 {
+		// span starts at the end of the line
+		start = offset
+	}
+	length := max(span.Length, 1)
+	if start+length > offset && start < offset {
+		length = offset - start
+	}
+
+	underline := strings.Repeat(" ", start) + strings.Repeat(marker, length)
+	if span.Label != "" {
+		underline += " " + span.Label
+	}
+
+	sb.WriteString(pad + " |\n")
+	sb.WriteString(fmt.Sprintf("%*d | %s\n", len(pad), span.Row, rendered.String()))
+	sb.WriteString(fmt.Sprintf("%s | %s\n", pad, underline))
+}
+
+// byteSpanToRunes converts the byte column and length of a span on a line to runes
+func byteSpanToRunes(line string, span Span) Span {
+	start := min(max(span.Col-1, 0), len(line))
+	end := min(start+max(span.Length, 0), len(line))
+	span.Col = utf8.RuneCountInString(line[:start]) + 1
+	span.Length = utf8.RuneCountInString(line[start:end])
+	return span
+}
+
+// sourceLine finds a 1 based line of a registered source file
+func (r *Reporter) sourceLine(file string, row int) (string, bool) {
+	content, ok := r.sources[file]
+	if !ok || row < 1 {
+		return "", false
+	}
+	lines := strings.Split(content, "\n")
+	if row > len(lines) {
+		return "", false
+	}
+	return strings.TrimRight(lines[row-1], "\r"), true
+}
@@ -0,0 +1,29 @@
+package diagnostics
+
+import "testing"
+
+// a secondary label on a line before the primary span is shown first, in source order
+func TestRenderSourceOrder(t *testing.T) {
+	r := InitializeReporter(false)
+	r.AddSource("main.txt", "@unsafe void f() {\n    int x = 1;\n    asm(\"movq $1, %0\" : \"=r\"(x));\n}")
+
+	r.Error(E_IMMUTABLE_BINDING, Span{File: "main.txt", Row: 3, Col: 30, Length: 1},
+		"cannot bind `x` to an asm output").
+		WithLabel("written by the asm").
+		WithSecondary(Span{File: "main.txt", Row: 2, Col: 9, Length: 1}, "`x` is declared here without `mut`").
+		WithNote("declare it `int mut x`")
+
+	want := "error[E0307]: cannot bind `x` to an asm output\n" +
+		" --> main.txt:3:30\n" +
+		"  |\n" +
+		"2 |     int x = 1;\n" +
+		"  |         - `x` is declared here without `mut`\n" +
+		"  |\n" +
+		"3 |     asm(\"movq $1, %0\" : \"=r\"(x));\n" +
+		"  |                              ^ written by the asm\n" +
+		"  |\n" +
+		"  = note: declare it `int mut x`\n"
+	if got := r.RenderAll(); got != want {
+		t.Errorf("rendered out of source order\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
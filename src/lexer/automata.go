@@ -0,0 +1,134 @@
+package lexer
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	debugger "github.com/CFdefense/compiler/src/debug"
+	"github.com/CFdefense/compiler/src/regex"
+)
+
+// Automata holds every automaton compiled from TokenRegexDefs
+// it is built once and never modified afterwards, so any number of
+// lexers can share it and read from it at the same time
+type Automata struct {
+	defs    []TokenRegexDef // definitions the automata were built from
+	nfas    []*NFA          // one NFA per definition
+	scanner *DFA            // every NFA combined into one minimized DFA
+	tables  *ScannerTables  // the scanner flattened into tables
+
+	modes map[LexerMode]*Automata // automata of the ModeRegexDefs of every mode
+}
+
+var (
+	compiledAutomata *Automata
+	compileOnce      sync.Once
+)
+
+// CompiledAutomata returns the shared automata, compiling them on first use
+func CompiledAutomata() *Automata {
+	compileOnce.Do(func() {
+		compiledAutomata = CompileAutomata(debugger.InitializeDebugger("LEX", false))
+	})
+	return compiledAutomata
+}
+
+// CompileAutomata runs the whole construction from the token definitions
+// 1. convert each token regex to an NFA (Thompson's Algorithm)
+// 2. combine the NFAs and convert them to one DFA (Subset Construction)
+// 3. minimize the DFA (Hopcroft's Algorithm)
+// the definitions of every lexer mode go through the same steps
+// most callers want the shared CompiledAutomata instead
+func CompileAutomata(debug *debugger.Debug) *Automata {
+	automata := compileDefs(TokenRegexDefs(), debug)
+	automata.modes = make(map[LexerMode]*Automata, len(lexerModes))
+	for _, mode := range lexerModes {
+		debug.DebugLog(fmt.Sprintf("lexer: building %s mode automata", mode), false)
+		automata.modes[mode] = compileDefs(ModeRegexDefs(mode), debug)
+	}
+	return automata
+}
+
+// compileDefs builds the NFAs, scanner and tables of one set of definitions
+func compileDefs(defs []TokenRegexDef, debug *debugger.Debug) *Automata {
+	nfas := buildTokenNFAs(defs, debug)
+	scanner := buildScanner(nfas, debug)
+
+	return &Automata{
+		defs:    defs,
+		nfas:    nfas,
+		scanner: scanner,
+		tables:  BuildScannerTables(scanner),
+	}
+}
+
+// Function to get the token definitions the automata were built from
+func (a *Automata) GetDefs() []TokenRegexDef {
+	return slices.Clone(a.defs)
+}
+
+// Function to get the NFA of every token definition, in definition order
+func (a *Automata) GetNFAs() []*NFA {
+	return slices.Clone(a.nfas)
+}
+
+// Function to get the minimized scanner DFA
+func (a *Automata) GetScanner() *DFA {
+	return a.scanner
+}
+
+// Function to get the scanner DFA as tables
+// the tables are shared and must not be modified
+func (a *Automata) GetTables() *ScannerTables {
+	return a.tables
+}
+
+// Function to get the automata of a lexer mode's definitions, see ModeRegexDefs
+// the automata of a mode have no modes of their own
+func (a *Automata) GetMode(mode LexerMode) *Automata {
+	return a.modes[mode]
+}
+
+// Thompson's algorithm implementation
+// compile each token regex pattern and convert it to an NFA
+// the token definitions are fixed, so a pattern that does not compile is a bug
+func buildTokenNFAs(defs []TokenRegexDef, debug *debugger.Debug) []*NFA {
+	debug.DebugLog("lexer: building NFAs", false)
+
+	nfas := make([]*NFA, 0, len(defs))
+	for _, regexDef := range defs {
+		re, err := regex.Compile(regexDef.Pattern)
+		if err != nil {
+			panic(fmt.Sprintf("lexer: token %s: %v", regexDef.Name, err))
+		}
+		nfa := NFAFromRegex(re, regexDef.TokenType)
+		debug.DebugLog(fmt.Sprintf("Pattern: %s -> NFA with %d states", regexDef.Pattern, len(re.GetNFA().States)), false)
+		nfa.Print(debug)
+		nfas = append(nfas, nfa)
+		debug.DebugLog(fmt.Sprintf("Added NFA %d for pattern %s with token type %s", len(nfas)-1, regexDef.Name, regexDef.TokenType.String()), false)
+	}
+
+	debug.DebugLog("lexer: success on NFAs", false)
+	return nfas
+}
+
+// Combine the token NFAs and convert them to a single DFA
+// the NFAs share one start state so the subset construction follows every
+// token at once, ties between tokens go to the earliest token definition
+func buildScanner(nfas []*NFA, debug *debugger.Debug) *DFA {
+	debug.DebugLog("lexer: combining NFAs into a single DFA", false)
+
+	combined := CombineNFAs(nfas)
+	dfa := ConvertNFAtoDFA(combined)
+	debug.DebugLog(fmt.Sprintf("lexer: subset construction produced %d states", len(dfa.states)), false)
+
+	minimized := dfa.Minimize()
+	debug.DebugLog(fmt.Sprintf("lexer: minimized scanner DFA to %d states", len(minimized.states)), false)
+
+	// Debug output - only print small DFAs to avoid log flooding
+	if len(minimized.states) < 50 {
+		debug.DebugLog(minimized.PrintDFA(), false)
+	}
+	return minimized
+}
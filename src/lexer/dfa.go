@@ -3,6 +3,7 @@ package lexer
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -10,14 +11,21 @@ type DFAState struct {
 	id          int
 	isAccepting bool
 	tokenType   TokenType
-	transitions map[string]*DFAState
-	nfaStates   []*NFAState // represented nfa state
+	priority    int             // priority of the NFA state that decided tokenType
+	transitions []dfaTransition // sorted by byte, the ranges never overlap
+	nfaStates   []*NFAState     // represented nfa state
+}
+
+// dfaTransition moves to a state on any byte of a range
+type dfaTransition struct {
+	byteRange
+	to *DFAState
 }
 
 type DFA struct {
-	start    *DFAState
-	states   []*DFAState
-	alphabet map[string]bool // epsilon-exclusive set of symbols
+	start   *DFAState
+	states  []*DFAState
+	classes []byteRange // byte classes of the NFA the DFA was built from, see byteClasses
 }
 
 var generateDFAStateID = func() func() int {
@@ -29,16 +37,18 @@ var generateDFAStateID = func() func() int {
 }()
 
 // convert nfa to dfa using subset construction
+// the NFA's byte classes are the alphabet, one move is computed per class
+// and neighbouring classes with the same target share one transition
 func ConvertNFAtoDFA(nfa *NFA) *DFA {
-	alphabet := getAlphabet(nfa)
+	classes := byteClasses(nfa)
 
 	initialNFAStates := nfa.epsilonClosure([]*NFAState{nfa.start})
 	initialDFAState := createDFAState(initialNFAStates)
 
 	dfa := &DFA{
-		start:    initialDFAState,
-		states:   []*DFAState{initialDFAState},
-		alphabet: alphabet,
+		start:   initialDFAState,
+		states:  []*DFAState{initialDFAState},
+		classes: classes,
 	}
 
 	stateMap := make(map[string]*DFAState)
@@ -51,8 +61,10 @@ func ConvertNFAtoDFA(nfa *NFA) *DFA {
 		currentState := queue[0]
 		queue = queue[1:] // remove first element
 
-		for symbol := range alphabet {
-			moveResult := move(currentState.nfaStates, symbol)
+		// classes are visited in byte order so state ids are the same on every run
+		moves := classMoves(currentState.nfaStates, classes)
+
+		for class, moveResult := range moves {
 			if len(moveResult) == 0 {
 				continue
 			}
@@ -74,10 +86,7 @@ func ConvertNFAtoDFA(nfa *NFA) *DFA {
 				queue = append(queue, nextDFAState)
 			}
 
-			if currentState.transitions == nil {
-				currentState.transitions = make(map[string]*DFAState)
-			}
-			currentState.transitions[symbol] = nextDFAState
+			currentState.addTransition(classes[class], nextDFAState)
 		}
 	}
 
@@ -88,19 +97,22 @@ func createDFAState(nfaStates []*NFAState) *DFAState {
 	dfaState := &DFAState{
 		id:          generateDFAStateID(),
 		isAccepting: false,
-		transitions: make(map[string]*DFAState),
 		nfaStates:   nfaStates,
 	}
 
 	// a DFA state is accepting if any of its NFA states is accepting
 	// if multiple accepting NFA states with different token types,
-	// choose the one with highest precedence (lowest enum value)
+	// choose the one from the earliest token definition (lowest priority)
+	// and then the one with highest precedence (lowest enum value)
 	for _, nfaState := range nfaStates {
-		if nfaState.isAccepting {
-			if !dfaState.isAccepting || nfaState.tokenType < dfaState.tokenType {
-				dfaState.isAccepting = true
-				dfaState.tokenType = nfaState.tokenType
-			}
+		if !nfaState.isAccepting {
+			continue
+		}
+		if !dfaState.isAccepting || nfaState.priority < dfaState.priority ||
+			(nfaState.priority == dfaState.priority && nfaState.tokenType < dfaState.tokenType) {
+			dfaState.isAccepting = true
+			dfaState.tokenType = nfaState.tokenType
+			dfaState.priority = nfaState.priority
 		}
 	}
 
@@ -119,114 +131,96 @@ func getNFAStateSetID(states []*NFAState) string {
 		if i > 0 {
 			sb.WriteString(",")
 		}
-		sb.WriteString(fmt.Sprintf("%d", id))
+		sb.WriteString(strconv.Itoa(id))
 	}
 	return sb.String()
 }
 
-func getAlphabet(nfa *NFA) map[string]bool {
-	alphabet := make(map[string]bool)
-	visited := make(map[int]bool)
-
-	var collectSymbols func(*NFAState)
-	collectSymbols = func(state *NFAState) {
-		if visited[state.id] {
-			return
-		}
-		visited[state.id] = true
-
-		for symbol := range state.transitions {
-			if symbol != "ε" && !isSpecialSymbol(symbol) {
-				alphabet[symbol] = true
-			}
-		}
+// classMoves computes move for every byte class in a single pass over the states
+// the result holds the target states of each class, in the order of the classes
+func classMoves(states []*NFAState, classes []byteRange) [][]*NFAState {
+	moves := make([][]*NFAState, len(classes))
+	seen := make([]map[int]bool, len(classes))
 
-		for _, targets := range state.transitions {
-			for _, target := range targets {
-				collectSymbols(target)
+	for _, state := range states {
+		for _, t := range state.transitions {
+			// the first class inside the range, classes never straddle a range
+			first := sort.Search(len(classes), func(i int) bool { return classes[i].hi >= t.lo })
+			for class := first; class < len(classes) && classes[class].lo <= t.hi; class++ {
+				if seen[class] == nil {
+					seen[class] = make(map[int]bool)
+				}
+				if !seen[class][t.to.id] {
+					seen[class][t.to.id] = true
+					moves[class] = append(moves[class], t.to)
+				}
 			}
 		}
 	}
 
-	collectSymbols(nfa.start)
-
-	// If alphabet is too large, use a more targeted approach
-	if len(alphabet) > 100 {
-		// Create a minimal alphabet with only commonly used characters
-		limitedAlphabet := make(map[string]bool)
-
-		// Add alphanumeric characters
-		for c := 'a'; c <= 'z'; c++ {
-			limitedAlphabet[string(c)] = true
-		}
-		for c := 'A'; c <= 'Z'; c++ {
-			limitedAlphabet[string(c)] = true
-		}
-		for c := '0'; c <= '9'; c++ {
-			limitedAlphabet[string(c)] = true
-		}
-
-		// Add common punctuation and operators
-		commonChars := []string{
-			"_", "+", "-", "*", "/", "%", "=", "!", "<", ">", "&", "|", "^",
-			"(", ")", "[", "]", "{", "}", ";", ",", ".", ":", "?", "@", "#",
-			"$", "~", "`", " ", "\t", "\n", "\r", "\"", "'", "\\",
-		}
+	return moves
+}
 
-		for _, char := range commonChars {
-			limitedAlphabet[char] = true
+// addTransition adds a transition after every transition the state already has
+// a range that continues the last one to the same state extends it instead
+func (state *DFAState) addTransition(input byteRange, target *DFAState) {
+	if last := len(state.transitions) - 1; last >= 0 {
+		previous := &state.transitions[last]
+		if previous.to == target && int(previous.hi)+1 == int(input.lo) {
+			previous.hi = input.hi
+			return
 		}
-		return limitedAlphabet
 	}
-
-	return alphabet
+	state.transitions = append(state.transitions, dfaTransition{byteRange: input, to: target})
 }
 
-func isSpecialSymbol(symbol string) bool {
-	specialSymbols := map[string]bool{
-		"word_boundary": true,
-		"start_anchor":  true,
-		"end_anchor":    true,
-		"any":           true,
+// next finds the state a byte moves to, nil when there is no transition
+func (state *DFAState) next(b byte) *DFAState {
+	i := sort.Search(len(state.transitions), func(i int) bool { return state.transitions[i].hi >= b })
+	if i < len(state.transitions) && state.transitions[i].lo <= b {
+		return state.transitions[i].to
 	}
-	return specialSymbols[symbol]
+	return nil
 }
 
-func move(states []*NFAState, symbol string) []*NFAState {
-	var result []*NFAState
-	seen := make(map[int]bool)
+// SimulateDFA runs the DFA over the whole input one byte at a time
+// returns whether the input is accepted and as which token type
+func (dfa *DFA) SimulateDFA(input string) (bool, TokenType) {
+	currentState := dfa.start
 
-	for _, state := range states {
-		if targets, exists := state.transitions[symbol]; exists {
-			for _, target := range targets {
-				if !seen[target.id] {
-					seen[target.id] = true
-					result = append(result, target)
-				}
-			}
+	for i := 0; i < len(input); i++ {
+		nextState := currentState.next(input[i])
+		if nextState == nil {
+			return false, 0 // reject
 		}
+		currentState = nextState
 	}
 
-	return result
+	// accept if the final state is accepting
+	return currentState.isAccepting, currentState.tokenType
 }
 
-func (dfa *DFA) SimulateDFA(input string) (bool, TokenType) {
-	currentState := dfa.start
+// Match finds the longest prefix of input starting at pos that the DFA accepts
+// returns the length of the match and its token type, a length of 0 means no match
+// this is a single pass, the walk stops as soon as there is no transition
+func (dfa *DFA) Match(input string, pos int) (int, TokenType) {
+	matchLength := 0
+	var matchType TokenType
 
-	for _, char := range input {
-		symbol := string(char)
-		nextState, exists := currentState.transitions[symbol]
-		if !exists {
-			nextState, exists = currentState.transitions["any"]
-			if !exists {
-				return false, 0 // reject
-			}
+	currentState := dfa.start
+	for i := pos; i < len(input); i++ {
+		nextState := currentState.next(input[i])
+		if nextState == nil {
+			break
 		}
 		currentState = nextState
+		if currentState.isAccepting {
+			matchLength = i + 1 - pos
+			matchType = currentState.tokenType
+		}
 	}
 
-	// accept if the final state is accepting
-	return currentState.isAccepting, currentState.tokenType
+	return matchLength, matchType
 }
 
 func (dfa *DFA) PrintDFA() string {
@@ -241,8 +235,8 @@ func (dfa *DFA) PrintDFA() string {
 		}
 		sb.WriteString(status + "\n")
 
-		for symbol, target := range state.transitions {
-			sb.WriteString(fmt.Sprintf("  %d --%s--> %d\n", state.id, symbol, target.id))
+		for _, t := range state.transitions {
+			sb.WriteString(fmt.Sprintf("  %d --%s--> %d\n", state.id, t.byteRange, t.to.id))
 		}
 	}
 
@@ -0,0 +1,166 @@
+package lexer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DumpAutomata writes Graphviz files of the automata of every token definition
+// each definition gets NAME.nfa.dot for its Thompson NFA, NAME.dfa.dot for the
+// subset construction and NAME.min.dfa.dot for the minimized DFA, the combined
+// scanner is written to scanner.dfa.dot. the automata of every lexer mode are
+// written the same way to a directory named after the mode, as asm/scanner.dfa.dot
+// render one with: dot -Tsvg PLUS.nfa.dot -o PLUS.nfa.svg
+func (a *Automata) DumpAutomata(dir string) error {
+	if err := a.dumpDefs(dir); err != nil {
+		return err
+	}
+	for _, mode := range lexerModes {
+		if err := a.GetMode(mode).dumpDefs(filepath.Join(dir, mode.String())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dumpDefs writes the automata of every definition and the scanner to dir
+func (a *Automata) dumpDefs(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create automata directory: %w", err)
+	}
+
+	files := map[string]string{
+		"scanner.dfa.dot": a.scanner.Dot("scanner"),
+	}
+	for i, def := range a.defs {
+		dfa := ConvertNFAtoDFA(a.nfas[i])
+		files[def.Name+".nfa.dot"] = a.nfas[i].Dot(def.Name + " NFA")
+		files[def.Name+".dfa.dot"] = dfa.Dot(def.Name + " DFA")
+		files[def.Name+".min.dfa.dot"] = dfa.Minimize().Dot(def.Name + " minimized DFA")
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Dot renders the NFA in the Graphviz dot language
+// states are numbered in breadth first order from the start state
+func (nfa *NFA) Dot(name string) string {
+	states := nfa.reachableStates()
+	index := make(map[*NFAState]int, len(states))
+	for i, state := range states {
+		index[state] = i
+	}
+
+	nodes := make([]dotNode, len(states))
+	var edges []dotEdge
+	for i, state := range states {
+		nodes[i] = dotNode{accepting: state.isAccepting, tokenType: state.tokenType}
+		for _, target := range state.epsilon {
+			edges = addDotEdge(edges, i, index[target], nil)
+		}
+		for _, t := range state.transitions {
+			edges = addDotEdge(edges, i, index[t.to], &t.byteRange)
+		}
+	}
+	return writeDot(name, nodes, edges)
+}
+
+// Dot renders the DFA in the Graphviz dot language
+// states are numbered in breadth first order from the start state
+func (dfa *DFA) Dot(name string) string {
+	index := map[*DFAState]int{dfa.start: 0}
+	order := []*DFAState{dfa.start}
+	var edges []dotEdge
+
+	for i := 0; i < len(order); i++ {
+		for _, t := range order[i].transitions {
+			if _, seen := index[t.to]; !seen {
+				index[t.to] = len(order)
+				order = append(order, t.to)
+			}
+			edges = addDotEdge(edges, i, index[t.to], &t.byteRange)
+		}
+	}
+
+	nodes := make([]dotNode, len(order))
+	for i, state := range order {
+		nodes[i] = dotNode{accepting: state.isAccepting, tokenType: state.tokenType}
+	}
+	return writeDot(name, nodes, edges)
+}
+
+// state of an automaton as it is drawn
+type dotNode struct {
+	accepting bool
+	tokenType TokenType
+}
+
+// every transition between two states, drawn as one edge
+type dotEdge struct {
+	from, to int
+	epsilon  bool
+	ranges   []byteRange
+}
+
+// addDotEdge adds a transition to the edge between two states, nil for an epsilon move
+// edges keep the order their first transition was added in
+func addDotEdge(edges []dotEdge, from, to int, input *byteRange) []dotEdge {
+	i := slices.IndexFunc(edges, func(e dotEdge) bool { return e.from == from && e.to == to })
+	if i == -1 {
+		edges = append(edges, dotEdge{from: from, to: to})
+		i = len(edges) - 1
+	}
+	if input == nil {
+		edges[i].epsilon = true
+	} else {
+		edges[i].ranges = append(edges[i].ranges, *input)
+	}
+	return edges
+}
+
+// writeDot renders states and edges as a left to right digraph
+// accepting states are double circles labelled with their token type
+func writeDot(name string, nodes []dotNode, edges []dotEdge) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("digraph %s {\n", dotQuote(name)))
+	sb.WriteString("\trankdir=LR;\n")
+	sb.WriteString("\tnode [shape=circle];\n")
+	sb.WriteString("\tstart [shape=point];\n")
+	sb.WriteString("\tstart -> 0;\n")
+
+	for i, node := range nodes {
+		if node.accepting {
+			sb.WriteString(fmt.Sprintf("\t%d [shape=doublecircle, label=%s];\n", i, dotQuote(fmt.Sprintf("%d\n%s", i, node.tokenType))))
+		}
+	}
+
+	for _, edge := range edges {
+		var labels []string
+		if edge.epsilon {
+			labels = append(labels, "ε")
+		}
+		if len(edge.ranges) > 0 {
+			labels = append(labels, formatByteRanges(edge.ranges))
+		}
+		sb.WriteString(fmt.Sprintf("\t%d -> %d [label=%s];\n", edge.from, edge.to, dotQuote(strings.Join(labels, " "))))
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// dotQuote quotes a string for use as a dot identifier or label
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
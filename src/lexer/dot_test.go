@@ -0,0 +1,76 @@
+package lexer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CFdefense/compiler/src/regex"
+)
+
+// the NFA of a[0-9b-d]|ae has epsilon moves around both branches, its minimized
+// DFA joins them into one edge with every byte range of the second character
+func TestDot(t *testing.T) {
+	nfa := NFAFromRegex(regex.MustCompile(`a[0-9b-d]|ae`), T_IDENTIFIER)
+	dfa := ConvertNFAtoDFA(nfa).Minimize()
+
+	checkDot(t, nfa.Dot("small NFA"),
+		`digraph "small NFA" {`,
+		"\tstart -> 0;\n",
+		"\t9 [shape=doublecircle, label=\"9\\nT_IDENTIFIER\"];\n",
+		"\t0 -> 1 [label=\"ε\"];\n",
+		"\t1 -> 3 [label=\"a\"];\n",
+		"\t5 -> 7 [label=\"0-9 b-d\"];\n",
+		"\t8 -> 9 [label=\"ε\"];\n",
+	)
+
+	dot := dfa.Dot("small DFA")
+	checkDot(t, dot,
+		`digraph "small DFA" {`,
+		"\t2 [shape=doublecircle, label=\"2\\nT_IDENTIFIER\"];\n",
+		"\t0 -> 1 [label=\"a\"];\n",
+		"\t1 -> 2 [label=\"0-9 b-e\"];\n",
+	)
+	if strings.Contains(dot, "ε") || strings.Count(dot, "doublecircle") != 1 {
+		t.Errorf("DFA dot has epsilon edges or more than one accepting state:\n%s", dot)
+	}
+}
+
+// checkDot checks that dot output holds every line
+func checkDot(t *testing.T, dot string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(dot, line) {
+			t.Errorf("dot is missing %q:\n%s", line, dot)
+		}
+	}
+}
+
+// every mode's automata are dumped to a directory of their own
+func TestDumpAutomata(t *testing.T) {
+	dir := t.TempDir()
+	if err := CompiledAutomata().DumpAutomata(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{
+		"scanner.dfa.dot",
+		"PLUS.nfa.dot",
+		"PLUS.min.dfa.dot",
+		filepath.Join("asm", "scanner.dfa.dot"),
+		filepath.Join("asm", "ASM_REGISTER.dfa.dot"),
+		filepath.Join("string", "STRING_FRAGMENT.nfa.dot"),
+		filepath.Join("interpolation", "INTERPOLATION_END.min.dfa.dot"),
+		filepath.Join("normal", "STRING_START.nfa.dot"),
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("%s was not written: %v", name, err)
+			continue
+		}
+		if !strings.HasPrefix(string(content), "digraph ") {
+			t.Errorf("%s is not a dot file:\n%s", name, content)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package lexer
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/CFdefense/compiler/src/regex"
+)
+
+// tokenOracle holds every token automaton next to the same pattern compiled by Go's regexp
+type tokenOracle struct {
+	defs      []TokenRegexDef
+	patterns  []*regexp.Regexp // each pattern anchored to match the whole input
+	nfas      []*NFA
+	dfas      []*DFA
+	minimized []*DFA
+
+	// automata of classPatterns, in the same order
+	classRegexps   []*regexp.Regexp
+	classNFAs      []*NFA
+	classMinimized []*DFA
+
+	modes map[LexerMode]*tokenOracle // the definitions of every lexer mode
+}
+
+// classPatterns are not token definitions, they cover negated and Unicode wide
+// classes whose transitions span most of the byte space
+var classPatterns = []string{
+	`[^"\\]*`,
+	`[\s\S]+`,
+	`.*`,
+	`[_a-zA-Z\x{80}-\x{10FFFF}][\w\x{80}-\x{10FFFF}]*`,
+	`[^a-z\x{400}-\x{4FF}\x{1F600}]+`,
+}
+
+var (
+	oracle     *tokenOracle
+	oracleOnce sync.Once
+)
+
+// getOracle builds the automata of every token definition once per test binary
+func getOracle() *tokenOracle {
+	oracleOnce.Do(func() {
+		automata := CompiledAutomata()
+		oracle = newTokenOracle(automata)
+		oracle.modes = make(map[LexerMode]*tokenOracle, len(lexerModes))
+		for _, mode := range lexerModes {
+			oracle.modes[mode] = newTokenOracle(automata.GetMode(mode))
+		}
+		for _, pattern := range classPatterns {
+			nfa := NFAFromRegex(regex.MustCompile(pattern), T_IDENTIFIER)
+			oracle.classRegexps = append(oracle.classRegexps, regexp.MustCompile(`^(?:`+pattern+`)$`))
+			oracle.classNFAs = append(oracle.classNFAs, nfa)
+			oracle.classMinimized = append(oracle.classMinimized, ConvertNFAtoDFA(nfa).Minimize())
+		}
+	})
+	return oracle
+}
+
+// newTokenOracle puts the automata of every definition next to its Go regexp
+func newTokenOracle(automata *Automata) *tokenOracle {
+	o := &tokenOracle{defs: automata.GetDefs(), nfas: automata.GetNFAs()}
+	for i, def := range o.defs {
+		o.patterns = append(o.patterns, regexp.MustCompile(`^(?:`+def.Pattern+`)$`))
+		dfa := ConvertNFAtoDFA(o.nfas[i])
+		o.dfas = append(o.dfas, dfa)
+		o.minimized = append(o.minimized, dfa.Minimize())
+	}
+	return o
+}
+
+// checkDefs checks the automata of every definition on the whole input
+// returns the index of the earliest definition matching it, -1 when none does
+func (o *tokenOracle) checkDefs(t *testing.T, input string) int {
+	want := -1
+	for i, def := range o.defs {
+		matched := o.patterns[i].MatchString(input)
+		if matched && want == -1 {
+			want = i
+		}
+		if got := o.nfas[i].Simulate(input); got != matched {
+			t.Errorf("%s NFA on %q: got %v, regexp %v", def.Name, input, got, matched)
+		}
+		if got, _ := o.dfas[i].SimulateDFA(input); got != matched {
+			t.Errorf("%s DFA on %q: got %v, regexp %v", def.Name, input, got, matched)
+		}
+		if got, _ := o.minimized[i].SimulateDFA(input); got != matched {
+			t.Errorf("%s minimized DFA on %q: got %v, regexp %v", def.Name, input, got, matched)
+		}
+	}
+	return want
+}
+
+// checkTables checks that tables find the longest prefix that some definition matches
+func (o *tokenOracle) checkTables(t *testing.T, name string, tables *ScannerTables, input string) {
+	wantLength, wantType := 0, TokenType(0)
+	for end := len(input); end > 0 && wantLength == 0; end-- {
+		for i, pattern := range o.patterns {
+			if pattern.MatchString(input[:end]) {
+				wantLength, wantType = end, o.defs[i].TokenType
+				break
+			}
+		}
+	}
+	if length, tokenType := tables.Match(input, 0); length != wantLength || (length > 0 && tokenType != wantType) {
+		t.Errorf("%s tables on %q: got %d %s, want %d %s", name, input, length, tokenType, wantLength, wantType)
+	}
+}
+
+// FuzzTokenAutomata checks the automata built for every TokenRegexDef against Go's regexp
+// each token NFA, its DFA and the minimized DFA must accept exactly the inputs the
+// pattern matches, and the combined scanner must pick the earliest definition that
+// matches, both as a DFA and as the generated tables. the ModeRegexDefs of every
+// lexer mode and their generated tables are checked the same way
+//
+// go test ./src/lexer -fuzz FuzzTokenAutomata
+// failing inputs are minimized by the fuzzer and saved under testdata/fuzz, where
+// they are kept as a regression corpus that every go test run checks again
+func FuzzTokenAutomata(f *testing.F) {
+	for _, seed := range []string{
+		"", "+", "|", "||", "->", "=>", "//", "\\", "\\n", "_", "x_1", "Z", "MAX_2",
+		"0", "0x1F", "0b101", "0x", "true", "falsey", "&mut", "if", "struct",
+		`"a\"b"`, `"open`, "'c'", `'\''`, "@", "`", "{", "ab cd",
+		"été", "变量_1", "Жz", "😀", "a\n\x00b", "\U0010FFFF",
+		"main:", "-8(%rbp,%rax,4)", "%st(1)", "$0x1F", "movq", `text\"{`,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		// Go's regexp reads invalid UTF-8 as U+FFFD, the automata only match valid UTF-8
+		if !utf8.ValidString(input) {
+			t.Skip()
+		}
+		o := getOracle()
+
+		// the earliest definition matching the whole input, which the scanner must report
+		want := o.checkDefs(t, input)
+
+		for i, pattern := range o.classRegexps {
+			matched := pattern.MatchString(input)
+			if got := o.classNFAs[i].Simulate(input); got != matched {
+				t.Errorf("%s NFA on %q: got %v, regexp %v", classPatterns[i], input, got, matched)
+			}
+			if got, _ := o.classMinimized[i].SimulateDFA(input); got != matched {
+				t.Errorf("%s minimized DFA on %q: got %v, regexp %v", classPatterns[i], input, got, matched)
+			}
+		}
+
+		accepted, tokenType := CompiledAutomata().GetScanner().SimulateDFA(input)
+		switch {
+		case want == -1 && accepted:
+			t.Errorf("scanner on %q: got %s, no definition matches", input, tokenType)
+		case want != -1 && (!accepted || tokenType != o.defs[want].TokenType):
+			t.Errorf("scanner on %q: got %v %s, want %s", input, accepted, tokenType, o.defs[want].Name)
+		}
+
+		o.checkTables(t, "scanner", GeneratedScannerTables(), input)
+
+		// every mode scanner is checked the same way against its own definitions
+		for _, mode := range lexerModes {
+			modeOracle := o.modes[mode]
+			modeOracle.checkDefs(t, input)
+			modeOracle.checkTables(t, mode.String()+" mode scanner", GeneratedModeTables(mode), input)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+// Command gen writes the lexer scanner tables
+//
+// it builds the token automata the same way the lexer used to at runtime,
+// from TokenRegexDefs through Thompson's construction, subset construction
+// and minimization, then stores the final DFA as Go arrays. the ModeRegexDefs
+// of every lexer mode get a DFA of their own the same way
+//
+//	go generate ./src/lexer
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/CFdefense/compiler/src/lexer"
+)
+
+func main() {
+	output := flag.String("o", "scanner_tables.go", "File to write the generated tables to")
+	flag.Parse()
+
+	source, err := lexer.GenerateScannerTables()
+	if err != nil {
+		log.Fatalf("Failed to generate scanner tables: %v", err)
+	}
+
+	if err := os.WriteFile(*output, source, 0644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *output, err)
+	}
+}
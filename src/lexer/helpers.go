@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"unicode/utf8"
+
+	"github.com/CFdefense/compiler/src/diagnostics"
 )
 
 // Helper function to check if a character is whitespace
@@ -86,6 +88,30 @@ func mapIdentifierToKeywordIfMatch(content string) TokenType {
 		return T_VOID_TYPE
 	case "int":
 		return T_INT_TYPE
+	case "float":
+		return T_FLOAT_TYPE
+	case "i8":
+		return T_I8_TYPE
+	case "i16":
+		return T_I16_TYPE
+	case "i32":
+		return T_I32_TYPE
+	case "i64":
+		return T_I64_TYPE
+	case "i128":
+		return T_I128_TYPE
+	case "u8":
+		return T_U8_TYPE
+	case "u16":
+		return T_U16_TYPE
+	case "u32":
+		return T_U32_TYPE
+	case "u64":
+		return T_U64_TYPE
+	case "u128":
+		return T_U128_TYPE
+	case "big":
+		return T_BIG_TYPE
 	case "bool":
 		return T_BOOL_TYPE
 	case "function":
@@ -120,44 +146,6 @@ func isKeywordTokenType(tokenType TokenType) bool {
 		tokenType == T_SIZEOF || tokenType == T_ASM || tokenType == T_FUNCTION
 }
 
-// isNumeric checks if a string represents a numeric literal
-func isNumeric(s string) bool {
-	if len(s) == 0 {
-		return false
-	}
-
-	// Check for hex prefix
-	if len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
-		for i := 2; i < len(s); i++ {
-			c := s[i]
-			if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')) {
-				return false
-			}
-		}
-		return true
-	}
-
-	// Check for binary prefix
-	if len(s) > 2 && s[0] == '0' && (s[1] == 'b' || s[1] == 'B') {
-		for i := 2; i < len(s); i++ {
-			c := s[i]
-			if c != '0' && c != '1' {
-				return false
-			}
-		}
-		return true
-	}
-
-	// Check for decimal number
-	for _, c := range s {
-		if c < '0' || c > '9' {
-			return false
-		}
-	}
-
-	return true
-}
-
 // createToken creates a new token with the given parameters
 func createToken(tokenType TokenType, lexeme string, row, col int) Token {
 	return Token{
@@ -168,16 +156,39 @@ func createToken(tokenType TokenType, lexeme string, row, col int) Token {
 	}
 }
 
+// addToken appends a token to the stream and records where it came from
+// start is the byte offset of the token in the content being tokenized
+// the end position is just past the last character of the lexeme
+// literal tokens are given their decoded value
+func (l *Lexer) addToken(token Token, start int) {
+	token.file = l.file
+	token.start = l.offset + start
+	token.end = token.start + len(token.lexeme)
+	token.end_row = token.row
+	token.end_col = token.col
+	last := token.lexeme
+	if line := strings.LastIndexByte(last, '\n'); line != -1 {
+		token.end_row += strings.Count(last, "\n")
+		token.end_col = 1
+		last = last[line+1:]
+	}
+	token.end_col += l.width(last)
+	l.decodeLiteral(&token)
+	l.attachTrivia(&token)
+	l.token_stream = append(l.token_stream, token)
+	l.trackMode(token)
+}
+
 // handleCompoundAssignment handles compound assignment operators like +=, -=, etc.
 func (l *Lexer) handleCompoundAssignment(operator string, operatorType TokenType, content string, pos int) (bool, int) {
 	// Create operator token
 	token1 := createToken(operatorType, operator, l.row, l.col)
-	l.token_stream = append(l.token_stream, token1)
+	l.addToken(token1, pos)
 	l.col += len(operator)
 
 	// Create assignment token
 	token2 := createToken(T_ASSIGN, "=", l.row, l.col)
-	l.token_stream = append(l.token_stream, token2)
+	l.addToken(token2, pos+len(operator))
 	l.col++
 
 	return true, pos + len(operator) + 1
@@ -187,41 +198,22 @@ func (l *Lexer) handleCompoundAssignment(operator string, operatorType TokenType
 func (l *Lexer) handleShiftAssignment(operator string, operatorType TokenType, content string, pos int) (bool, int) {
 	// Create operator token
 	token1 := createToken(operatorType, operator, l.row, l.col)
-	l.token_stream = append(l.token_stream, token1)
+	l.addToken(token1, pos)
 	l.col += len(operator)
 
 	// Create assignment token
 	token2 := createToken(T_ASSIGN, "=", l.row, l.col)
-	l.token_stream = append(l.token_stream, token2)
+	l.addToken(token2, pos+len(operator))
 	l.col++
 
 	return true, pos + len(operator) + 1
 }
 
-// isInASMContext checks if the current position is inside an ASM block
-func (l *Lexer) isInASMContext(content string, pos int) bool {
-	// Look backwards to see if we're inside an asm block
-	for i := pos - 1; i >= 0; i-- {
-		if content[i] == '}' {
-			return false // Found closing brace, not in ASM context
-		} else if content[i] == '{' {
-			// Look for 'asm' before the opening brace
-			j := i - 1
-			for j >= 0 && isWhitespace(content[j]) {
-				j--
-			}
-			if j >= 2 && content[j-2:j+1] == "asm" {
-				return true
-			}
-			return false
-		}
-	}
-	return false
-}
-
 // handleWhitespace handles whitespace characters
+// they are kept as trivia for the tokens on either side of them
 func (l *Lexer) handleWhitespace(content string, pos int) (bool, int) {
 	if isWhitespace(content[pos]) {
+		l.trivia = append(l.trivia, content[pos])
 		if content[pos] == '\n' {
 			l.row++
 			l.col = 1
@@ -234,95 +226,117 @@ func (l *Lexer) handleWhitespace(content string, pos int) (bool, int) {
 }
 
 // handleSingleLineComment handles single-line comments
+// # starts a comment that runs to the end of the line wherever it appears, in asm
+// blocks too. // is always floor division, except that with legacy comments on a
+// // that starts a line is still read as a comment and warned about
 func (l *Lexer) handleSingleLineComment(content string, pos int) (bool, int) {
-	if pos < len(content) && content[pos] == '#' {
-		// Check if this is followed by another # (then it's not a comment)
-		if pos+1 < len(content) && content[pos+1] == '#' {
-			// Treat as two separate # tokens
-			token1 := createToken(T_HASH, "#", l.row, l.col)
-			l.token_stream = append(l.token_stream, token1)
-			l.col++
-
-			token2 := createToken(T_HASH, "#", l.row, l.col)
-			l.token_stream = append(l.token_stream, token2)
-			l.col++
-			pos += 2
-			return true, pos
-		}
-
-		// Check if we're in an ASM context (inside asm { ... })
-		inASMContext := l.isInASMContext(content, pos)
-
-		// Treat as comment if it's at start of line, preceded by whitespace, or in ASM context
-		isComment := false
-		if pos == 0 {
-			isComment = true
-		} else if inASMContext {
-			isComment = true
-		} else {
-			// Check if previous character is whitespace
-			prevChar := content[pos-1]
-			if prevChar == ' ' || prevChar == '\t' || prevChar == '\n' || prevChar == '\r' {
-				isComment = true
-			}
-			// Also treat as comment if preceded by certain ASM-related characters
-			if prevChar == '}' || prevChar == ',' || prevChar == ')' {
-				isComment = true
-			}
-		}
+	legacy := l.legacy_comments && strings.HasPrefix(content[pos:], "//") &&
+		!strings.HasPrefix(content[pos:], "///") && l.atLineStart()
+	if content[pos] != '#' && !legacy {
+		return false, pos
+	}
 
-		// Only treat as comment if it's at start of line or followed by non-whitespace
-		if isComment {
-			end := pos + 1
-			// Continue to end of line, but don't consume the newline character
-			for end < len(content) && content[end] != '\n' && content[end] != '\r' {
-				end++
-			}
-			commentText := content[pos:end]
-			token := createToken(T_SINGLE_LINE_COMMENT, commentText, l.row, l.col)
-			l.token_stream = append(l.token_stream, token)
-			// Update col and pos
-			l.col += end - pos
-			pos = end
-			return true, pos
-		} else {
-			// Not a comment, treat as T_HASH
-			token := createToken(T_HASH, "#", l.row, l.col)
-			l.token_stream = append(l.token_stream, token)
-			l.col++
-			pos++
-			return true, pos
-		}
+	end := pos + 1
+	// Continue to end of line, but don't consume the newline character
+	for end < len(content) && content[end] != '\n' && content[end] != '\r' {
+		end++
 	}
-	return false, pos
+	commentText := content[pos:end]
+	token := createToken(T_SINGLE_LINE_COMMENT, commentText, l.row, l.col)
+	l.addToken(token, pos)
+	if legacy {
+		l.diagnostics.Warning(diagnostics.W_LEGACY_COMMENT, l.span(2),
+			"`//` comments are deprecated").
+			WithLabel("write `#` instead").
+			WithNote("`//` is floor division, it is only read as a comment at the start of a line with legacy comments on")
+	}
+	l.checkUTF8(commentText, pos)
+	l.col += l.width(commentText)
+	return true, end
 }
 
 // handleMultiLineComment handles multi-line comments
+// block comments nest, /** starts a doc comment unless it is all stars like /**/ or /***
 func (l *Lexer) handleMultiLineComment(content string, pos int) (bool, int) {
 	if pos+2 <= len(content) && content[pos:pos+2] == "/*" {
-		end := pos + 2
-		for end+1 < len(content) && !(content[end] == '*' && content[end+1] == '/') {
-			if content[end] == '\n' {
-				l.row++
-				l.col = 1
-			} else {
-				l.col++
-			}
-			end++
+		tokenType := T_MULTI_LINE_COMMENT
+		if pos+3 < len(content) && content[pos+2] == '*' && content[pos+3] != '*' && content[pos+3] != '/' {
+			tokenType = T_MULTI_LINE_DOC_COMMENT
 		}
-		if end+1 < len(content) {
-			end += 2 // include closing */
+		end, unclosed := commentEnd(content, pos)
+		if end == -1 {
+			// an unterminated comment runs to the end of the file
+			end = len(content)
 		}
 		commentText := content[pos:end]
-		token := createToken(T_MULTI_LINE_COMMENT, commentText, l.row, l.col)
-		l.token_stream = append(l.token_stream, token)
-		l.col += end - pos
+		token := createToken(tokenType, commentText, l.row, l.col)
+		l.addToken(token, pos)
+		if len(unclosed) > 0 {
+			l.handleUnterminatedComment(l.token_stream[len(l.token_stream)-1], pos, unclosed)
+		}
+		l.checkUTF8(commentText, pos)
+		l.updatePosition(commentText)
 		pos = end
 		return true, pos
 	}
 	return false, pos
 }
 
+// handleSingleLineDocComment handles /// doc comments, which run to the end of the line
+func (l *Lexer) handleSingleLineDocComment(content string, pos int) (bool, int) {
+	if !strings.HasPrefix(content[pos:], "///") {
+		return false, pos
+	}
+	end := pos + 3
+	for end < len(content) && content[end] != '\n' && content[end] != '\r' {
+		end++
+	}
+	commentText := content[pos:end]
+	token := createToken(T_SINGLE_LINE_DOC_COMMENT, commentText, l.row, l.col)
+	l.addToken(token, pos)
+	l.checkUTF8(commentText, pos)
+	l.col += l.width(commentText)
+	return true, end
+}
+
+// commentEnd returns the position after the */ that closes the block comment at pos
+// every nested /* needs its own */, when one is missing the end is -1 and
+// unclosed holds the position of each comment still open, outermost first
+func commentEnd(content string, pos int) (end int, unclosed []int) {
+	for i := pos; i < len(content); {
+		switch {
+		case strings.HasPrefix(content[i:], "/*"):
+			unclosed = append(unclosed, i)
+			i += 2
+		case strings.HasPrefix(content[i:], "*/"):
+			unclosed = unclosed[:len(unclosed)-1]
+			i += 2
+			if len(unclosed) == 0 {
+				return i, nil
+			}
+		default:
+			i++
+		}
+	}
+	return -1, unclosed
+}
+
+// handleUnterminatedComment reports a block comment that reaches the end of the file
+// the error points at the opening of the comment, and at the innermost nested
+// comment that was left open when that is a different one
+func (l *Lexer) handleUnterminatedComment(token Token, pos int, unclosed []int) {
+	diagnostic := l.diagnostics.Error(diagnostics.E_UNTERMINATED_COMMENT, l.span(2),
+		"unterminated block comment").
+		WithLabel("comment starts here")
+	if len(unclosed) > 1 {
+		innermost := unclosed[len(unclosed)-1] - pos
+		diagnostic.WithSecondary(l.spanAt(token, innermost, 2), "nested comment is never closed").
+			WithNote(fmt.Sprintf("block comments nest, %d `*/` are missing before the end of the file", len(unclosed)))
+		return
+	}
+	diagnostic.WithNote("no closing `*/` before the end of the file")
+}
+
 // handleOperator handles operators and special characters
 func (l *Lexer) handleOperator(content string, pos int) (bool, int) {
 	// Handle compound assignment operators
@@ -342,7 +356,7 @@ func (l *Lexer) handleOperator(content string, pos int) (bool, int) {
 		case "&=":
 			return l.handleCompoundAssignment("&", T_AMPERSAND, content, pos)
 		case "|=":
-			return l.handleCompoundAssignment("|", T_OR, content, pos)
+			return l.handleCompoundAssignment("|", T_PIPE, content, pos)
 		case "^=":
 			return l.handleCompoundAssignment("^", T_XOR, content, pos)
 		case "<<=":
@@ -355,10 +369,10 @@ func (l *Lexer) handleOperator(content string, pos int) (bool, int) {
 	// Handle &mut as separate tokens
 	if pos+4 <= len(content) && content[pos:pos+4] == "&mut" {
 		token1 := createToken(T_AMPERSAND, "&", l.row, l.col)
-		l.token_stream = append(l.token_stream, token1)
+		l.addToken(token1, pos)
 		l.col++
 		token2 := createToken(T_MUT, "mut", l.row, l.col)
-		l.token_stream = append(l.token_stream, token2)
+		l.addToken(token2, pos+1)
 		l.col += 3
 		pos += 4
 		return true, pos
@@ -373,10 +387,10 @@ func (l *Lexer) handleOperator(content string, pos int) (bool, int) {
 		} else {
 			// This is prefix increment
 			token1 := createToken(T_PLUS, "+", l.row, l.col)
-			l.token_stream = append(l.token_stream, token1)
+			l.addToken(token1, pos)
 			l.col++
 			token2 := createToken(T_PLUS, "+", l.row, l.col)
-			l.token_stream = append(l.token_stream, token2)
+			l.addToken(token2, pos+1)
 			l.col++
 			pos += 2
 			return true, pos
@@ -391,10 +405,10 @@ func (l *Lexer) handleOperator(content string, pos int) (bool, int) {
 		} else {
 			// This is prefix decrement
 			token1 := createToken(T_MINUS, "-", l.row, l.col)
-			l.token_stream = append(l.token_stream, token1)
+			l.addToken(token1, pos)
 			l.col++
 			token2 := createToken(T_MINUS, "-", l.row, l.col)
-			l.token_stream = append(l.token_stream, token2)
+			l.addToken(token2, pos+1)
 			l.col++
 			pos += 2
 			return true, pos
@@ -402,159 +416,52 @@ func (l *Lexer) handleOperator(content string, pos int) (bool, int) {
 	}
 
 	if pos < len(content) && content[pos] == '%' {
-		// Check if it's followed by a letter (then it's a register)
-		if pos+1 < len(content) && ((content[pos+1] >= 'a' && content[pos+1] <= 'z') ||
-			(content[pos+1] >= 'A' && content[pos+1] <= 'Z')) {
-			// Check if we're in an ASM context (inside asm { ... })
-			if l.isInASMContext(content, pos) {
-				// Let the ASM register handling take care of it
-			} else {
-				// Not in ASM context, treat as modulo operator
-				token := createToken(T_MODULO, "%", l.row, l.col)
-				l.token_stream = append(l.token_stream, token)
-				l.col++
-				pos++
-				return true, pos
-			}
-		} else {
-			// Standalone % - treat as T_MODULO
-			token := createToken(T_MODULO, "%", l.row, l.col)
-			l.token_stream = append(l.token_stream, token)
-			l.col++
-			pos++
-			return true, pos
-		}
+		// registers are only lexed in asm blocks, anywhere else % is modulo
+		token := createToken(T_MODULO, "%", l.row, l.col)
+		l.addToken(token, pos)
+		l.col++
+		pos++
+		return true, pos
 	}
 	if pos+2 <= len(content) && content[pos:pos+2] == ":=" {
 		token := createToken(T_DECLARE_ASSIGN, ":=", l.row, l.col)
-		l.token_stream = append(l.token_stream, token)
+		l.addToken(token, pos)
 		l.col += 2
 		pos += 2
 		return true, pos
 	}
 	if pos < len(content) && content[pos] == '"' {
-		end := pos + 1
-		for end < len(content) && content[end] != '"' {
-			if content[end] == '\\' && end+1 < len(content) {
-				// Skip escaped character
-				end += 2
-			} else {
-				end++
-			}
-		}
-		if end < len(content) {
-			end++ // include closing quote
+		if end := closingQuote(content, pos); end != -1 {
 			stringText := content[pos:end]
 			token := createToken(T_STRING_LITERAL, stringText, l.row, l.col)
-			l.token_stream = append(l.token_stream, token)
-			l.col += end - pos
+			l.addToken(token, pos)
+			l.checkUTF8(stringText, pos)
+			l.updatePosition(stringText)
 			pos = end
 			return true, pos
 		}
+		return l.handleUnterminatedLiteral(content, pos, "string", `"`)
 	}
 	if pos < len(content) && content[pos] == '\'' {
-		end := pos + 1
-		for end < len(content) && content[end] != '\'' {
-			if content[end] == '\\' && end+1 < len(content) {
-				// Skip escaped character
-				end += 2
-			} else {
-				end++
-			}
-		}
-		if end < len(content) {
-			end++ // include closing quote
+		if end := closingQuote(content, pos); end != -1 {
 			charText := content[pos:end]
 			token := createToken(T_CHAR_LITERAL, charText, l.row, l.col)
-			l.token_stream = append(l.token_stream, token)
-			l.col += end - pos
+			l.addToken(token, pos)
+			l.checkUTF8(charText, pos)
+			l.updatePosition(charText)
 			pos = end
 			return true, pos
 		}
+		return l.handleUnterminatedLiteral(content, pos, "character", "'")
 	}
 	if pos < len(content) && content[pos] == '$' {
-		// Check if it's followed by a digit (then it's an ASM immediate value)
-		if pos+1 < len(content) && content[pos+1] >= '0' && content[pos+1] <= '9' {
-			// Let the ASM immediate value handling take care of it
-		} else {
-			// Standalone $ - treat as T_DOLLAR
-			token := createToken(T_DOLLAR, "$", l.row, l.col)
-			l.token_stream = append(l.token_stream, token)
-			l.col++
-			pos++
-			return true, pos
-		}
-	}
-	if pos < len(content) && content[pos] == '$' {
-		// Find the end of the immediate value
-		end := pos + 1
-		for end < len(content) && ((content[end] >= '0' && content[end] <= '9') ||
-			(content[end] >= 'a' && content[end] <= 'f') ||
-			(content[end] >= 'A' && content[end] <= 'F') ||
-			content[end] == 'x' || content[end] == 'X') &&
-			content[end] != ',' && content[end] != ' ' &&
-			content[end] != '\t' && content[end] != '\n' && content[end] != '#' {
-			end++
-		}
-		immediateText := content[pos:end]
-		token := createToken(T_IDENTIFIER, immediateText, l.row, l.col) // Treat as identifier for ASM
-		l.token_stream = append(l.token_stream, token)
-		l.col += end - pos
-		pos = end
+		// immediates are only lexed in asm blocks
+		token := createToken(T_DOLLAR, "$", l.row, l.col)
+		l.addToken(token, pos)
+		l.col++
+		pos++
 		return true, pos
 	}
-	if pos < len(content) && content[pos] == '%' {
-		// Find the end of the register name
-		end := pos + 1
-		parenCount := 0
-		for end < len(content) && ((content[end] >= 'a' && content[end] <= 'z') ||
-			(content[end] >= 'A' && content[end] <= 'Z') ||
-			(content[end] >= '0' && content[end] <= '9') ||
-			content[end] == 'x' || content[end] == 'i' || content[end] == 'p' ||
-			content[end] == 's' || content[end] == 'd' || content[end] == 'b' ||
-			content[end] == 'a' || content[end] == 'c' || content[end] == 'e' ||
-			content[end] == 'g' || content[end] == 'h' || content[end] == 'l' ||
-			content[end] == 'o' || content[end] == 'u' || content[end] == 'w' ||
-			content[end] == 'y' || content[end] == 'z' || content[end] == 'm' ||
-			content[end] == 'n' || content[end] == 'q' || content[end] == 'r' ||
-			content[end] == 't' || content[end] == 'v' || content[end] == 'f' ||
-			content[end] == 'k' || content[end] == 'j' ||
-			content[end] == '(' || content[end] == ')' || content[end] == ',' ||
-			content[end] == '1' || content[end] == '2' || content[end] == '3' ||
-			content[end] == '4' || content[end] == '5' || content[end] == '6' ||
-			content[end] == '7' || content[end] == '8' || content[end] == '9' ||
-			content[end] == '0') {
-
-			// Handle parentheses for register names like %st(1)
-			if content[end] == '(' {
-				parenCount++
-			} else if content[end] == ')' {
-				parenCount--
-				if parenCount < 0 {
-					break // Unmatched closing parenthesis
-				}
-			}
-
-			// Stop if we hit whitespace or other delimiters (but allow parentheses)
-			if content[end] == ',' && parenCount == 0 {
-				break
-			} else if content[end] == ' ' || content[end] == '\t' || content[end] == '\n' || content[end] == '#' {
-				break
-			}
-
-			end++
-		}
-
-		// Only create the token if we have a valid register name
-		if end > pos+1 {
-			registerText := content[pos:end]
-			token := createToken(T_IDENTIFIER, registerText, l.row, l.col) // Treat as identifier for now
-			l.token_stream = append(l.token_stream, token)
-			l.col += end - pos
-			pos = end
-			return true, pos
-		}
-	}
 	if pos < len(content) && content[pos] == '_' {
 		// Check if it's followed by a word character (then it's part of an identifier)
 		if pos+1 < len(content) && isWordChar(content[pos+1]) {
@@ -562,7 +469,7 @@ func (l *Lexer) handleOperator(content string, pos int) (bool, int) {
 		} else {
 			// Standalone underscore - treat as T_UNDERSCORE
 			token := createToken(T_UNDERSCORE, "_", l.row, l.col)
-			l.token_stream = append(l.token_stream, token)
+			l.addToken(token, pos)
 			l.col++
 			pos++
 			return true, pos
@@ -579,14 +486,17 @@ func (l *Lexer) handleOperator(content string, pos int) (bool, int) {
 		if consecutiveCommas >= 2 {
 			// Split consecutive commas: first comma as T_COMMA, rest as T_ERROR
 			firstCommaToken := createToken(T_COMMA, ",", l.row, l.col)
-			l.token_stream = append(l.token_stream, firstCommaToken)
+			l.addToken(firstCommaToken, pos)
 			l.col++
 
 			// Create T_ERROR token for remaining commas
 			remainingCommas := strings.Repeat(",", consecutiveCommas-1)
 			errorToken := createToken(T_ERROR, remainingCommas, l.row, l.col)
-			l.token_stream = append(l.token_stream, errorToken)
-			l.debug.DebugLog(fmt.Sprintf("Consecutive commas: %s + %s at %d:%d", firstCommaToken.lexeme, errorToken.lexeme, l.row, l.col), true)
+			l.addToken(errorToken, pos+1)
+			l.debug.DebugLog(fmt.Sprintf("Consecutive commas: %s + %s at %d:%d", firstCommaToken.lexeme, errorToken.lexeme, l.row, l.col), false)
+			l.diagnostics.Error(diagnostics.E_REPEATED_COMMA, l.span(len(remainingCommas)),
+				"repeated comma").
+				WithLabel("nothing between these commas")
 
 			// Update position
 			for i := 1; i < consecutiveCommas; i++ {
@@ -596,66 +506,11 @@ func (l *Lexer) handleOperator(content string, pos int) (bool, int) {
 			return true, pos
 		}
 	}
-	if pos < len(content) && content[pos] == '-' {
-		// Check if we're in an ASM context (inside asm { ... })
-		if l.isInASMContext(content, pos) {
-			// Check if this negative number is followed by a parenthesis (memory addressing)
-			if pos+1 < len(content) && content[pos+1] >= '0' && content[pos+1] <= '9' {
-				numberEnd := pos + 1
-				for numberEnd < len(content) && content[numberEnd] >= '0' && content[numberEnd] <= '9' {
-					numberEnd++
-				}
-
-				if numberEnd < len(content) && content[numberEnd] == '(' {
-					// This is ASM memory addressing with negative number, treat as identifier
-					numberText := content[pos:numberEnd]
-					token := createToken(T_IDENTIFIER, numberText, l.row, l.col)
-					l.token_stream = append(l.token_stream, token)
-					l.col += numberEnd - pos
-					pos = numberEnd
-					return true, pos
-				}
-			}
-		}
-	}
 	if pos < len(content) && content[pos] >= '0' && content[pos] <= '9' {
-		// Check if we're in an ASM context (inside asm { ... })
-		inASMContext := l.isInASMContext(content, pos)
-
-		// Check if this number is followed by a parenthesis (memory addressing)
-		numberEnd := pos
-		for numberEnd < len(content) && content[numberEnd] >= '0' && content[numberEnd] <= '9' {
-			numberEnd++
-		}
-
-		if numberEnd < len(content) && content[numberEnd] == '(' {
-			// This is ASM memory addressing, treat the number as identifier
-			numberText := content[pos:numberEnd]
-			token := createToken(T_IDENTIFIER, numberText, l.row, l.col)
-			l.token_stream = append(l.token_stream, token)
-			l.col += numberEnd - pos
-			pos = numberEnd
-			return true, pos
-		} else if inASMContext {
-			// In ASM context, treat standalone numbers as identifiers
-			numberEnd := pos
-			for numberEnd < len(content) && content[numberEnd] >= '0' && content[numberEnd] <= '9' {
-				numberEnd++
-			}
-			numberText := content[pos:numberEnd]
-			token := createToken(T_IDENTIFIER, numberText, l.row, l.col)
-			l.token_stream = append(l.token_stream, token)
-			l.col += numberEnd - pos
-			pos = numberEnd
-			return true, pos
-		}
-	}
-	if pos < len(content) && content[pos] >= '0' && content[pos] <= '9' {
-		// Find the end of the number part
-		numberEnd := pos
-		for numberEnd < len(content) && content[numberEnd] >= '0' && content[numberEnd] <= '9' {
-			numberEnd++
-		}
+		// Find the end of the number part, prefixes, fractions and suffixes
+		// are part of the number when the scanner reads them as one
+		numberLength, _ := l.scanner.Match(content, pos)
+		numberEnd := pos + numberLength
 
 		// Check if there's an identifier part after the number
 		if numberEnd < len(content) && isWordChar(content[numberEnd]) {
@@ -667,13 +522,13 @@ func (l *Lexer) handleOperator(content string, pos int) (bool, int) {
 
 			// Create number token
 			numberText := content[pos:numberEnd]
-			numberToken := createToken(T_INT_LITERAL, numberText, l.row, l.col)
-			l.token_stream = append(l.token_stream, numberToken)
+			numberToken := createToken(numberTokenType(numberText), numberText, l.row, l.col)
+			l.addToken(numberToken, pos)
 
 			// Create identifier token
 			identifierText := content[numberEnd:identifierEnd]
 			identifierToken := createToken(T_IDENTIFIER, identifierText, l.row, l.col+len(numberText))
-			l.token_stream = append(l.token_stream, identifierToken)
+			l.addToken(identifierToken, numberEnd)
 
 			// Update position
 			for _, c := range content[pos:identifierEnd] {
@@ -691,6 +546,116 @@ func (l *Lexer) handleOperator(content string, pos int) (bool, int) {
 	return false, pos
 }
 
+// closingQuote finds the end of a literal whose opening quote is at pos
+// escaped characters are skipped, returns the position after the closing
+// quote or -1 if the literal is never closed
+func closingQuote(content string, pos int) int {
+	quote := content[pos]
+	for end := pos + 1; end < len(content); end++ {
+		switch content[end] {
+		case quote:
+			return end + 1
+		case '\\':
+			// Skip escaped character
+			end++
+		}
+	}
+	return -1
+}
+
+// literalPrefix recognizes the start of a raw string, byte string or byte character
+// returns the length of the prefix up to and including the opening quote and the
+// delimiter that closes the literal, or 0 when no such literal starts at pos
+func literalPrefix(content string, pos int) (int, string) {
+	if pos+1 >= len(content) {
+		return 0, ""
+	}
+	switch content[pos] {
+	case 'b':
+		if content[pos+1] == '"' || content[pos+1] == '\'' {
+			return 2, content[pos+1 : pos+2]
+		}
+	case 'r':
+		hashes := 0
+		for pos+1+hashes < len(content) && content[pos+1+hashes] == '#' {
+			hashes++
+		}
+		if pos+1+hashes < len(content) && content[pos+1+hashes] == '"' {
+			return 2 + hashes, `"` + strings.Repeat("#", hashes)
+		}
+	}
+	return 0, ""
+}
+
+// handlePrefixedLiteral handles raw strings r"..." and r#"..."#, byte strings b"..."
+// and byte characters b'x'. a raw string has no escapes and ends at the first quote
+// followed by as many # as it opened with, so r#"say "hi""# holds its quotes
+func (l *Lexer) handlePrefixedLiteral(content string, pos int) (bool, int) {
+	prefix, closing := literalPrefix(content, pos)
+	if prefix == 0 {
+		return false, pos
+	}
+
+	var tokenType TokenType
+	var kind string
+	end := -1
+	switch {
+	case content[pos] == 'r':
+		tokenType, kind = T_RAW_STRING_LITERAL, "raw string"
+		if length := strings.Index(content[pos+prefix:], closing); length != -1 {
+			end = pos + prefix + length + len(closing)
+		}
+	case closing == `"`:
+		tokenType, kind = T_BYTE_STRING_LITERAL, "byte string"
+		end = closingQuote(content, pos+1)
+	default:
+		tokenType, kind = T_BYTE_CHAR_LITERAL, "byte"
+		end = closingQuote(content, pos+1)
+	}
+	if end == -1 {
+		return l.handleUnterminatedLiteral(content, pos, kind, closing)
+	}
+
+	literalText := content[pos:end]
+	token := createToken(tokenType, literalText, l.row, l.col)
+	l.addToken(token, pos)
+	l.checkUTF8(literalText, pos)
+	l.updatePosition(literalText)
+	return true, end
+}
+
+// atAsmInstruction checks if the next word of an asm block is an instruction
+func (l *Lexer) atAsmInstruction() bool {
+	switch l.previous.token_type {
+	case T_OPENING_BRACE, T_SEMICOLON, T_ASM_LABEL:
+		return true
+	case T_ASM_INSTRUCTION:
+		if ASM_PREFIXES[l.previous.lexeme] {
+			return true
+		}
+	}
+	return l.atLineStart()
+}
+
+// handleUnterminatedLiteral handles a quote that is never closed
+// the rest of the line becomes an error token so lexing picks up again on the next one
+func (l *Lexer) handleUnterminatedLiteral(content string, pos int, kind string, closing string) (bool, int) {
+	end := pos + 1
+	for end < len(content) && content[end] != '\n' && content[end] != '\r' {
+		end++
+	}
+	literalText := content[pos:end]
+	token := createToken(T_ERROR, literalText, l.row, l.col)
+	l.addToken(token, pos)
+	l.diagnostics.Error(diagnostics.E_UNTERMINATED_LITERAL, l.span(1),
+		"unterminated %s literal", kind).
+		WithLabel(kind + " starts here").
+		WithNote(fmt.Sprintf("no closing `%s` before the end of the file", closing))
+	l.checkUTF8(literalText, pos)
+	l.col += l.width(literalText)
+	return true, end
+}
+
 // handleUnknownToken handles unknown or invalid tokens
 func (l *Lexer) handleUnknownToken(content string, pos int) (bool, int) {
 	// Handle Unicode characters properly
@@ -698,26 +663,108 @@ func (l *Lexer) handleUnknownToken(content string, pos int) (bool, int) {
 	currentChar := string(rune)
 
 	// Check if this is a valid Unicode character
-	if rune == utf8.RuneError {
+	// a size of 1 tells an invalid byte apart from an encoded U+FFFD
+	if rune == utf8.RuneError && size == 1 {
 		// Invalid UTF-8 sequence, treat as single byte
-		token := createToken(T_UNKNOWN, string(content[pos]), l.row, l.col)
-		l.token_stream = append(l.token_stream, token)
-		l.debug.DebugLog(fmt.Sprintf("Invalid UTF-8 token: %c at %d:%d", content[pos], l.row, l.col), true)
+		token := createToken(T_UNKNOWN, content[pos:pos+1], l.row, l.col)
+		l.addToken(token, pos)
+		l.debug.DebugLog(fmt.Sprintf("Invalid UTF-8 token: %c at %d:%d", content[pos], l.row, l.col), false)
+		l.diagnostics.Error(diagnostics.E_INVALID_UTF8, l.span(1),
+			"invalid UTF-8 byte 0x%02x at byte offset %d", content[pos], l.offset+pos).
+			WithLabel("not valid UTF-8")
 		l.col++
 		return true, pos + 1
 	} else if rune > 127 || (rune >= 0 && rune <= 31) || rune == 127 {
 		// This is a Unicode character or control character, create a single T_ERROR token
 		token := createToken(T_ERROR, currentChar, l.row, l.col)
-		l.token_stream = append(l.token_stream, token)
-		l.debug.DebugLog(fmt.Sprintf("Unicode/control error token: %s at %d:%d", currentChar, l.row, l.col), true)
-		l.col++
+		l.addToken(token, pos)
+		l.debug.DebugLog(fmt.Sprintf("Unicode/control error token: %s at %d:%d", currentChar, l.row, l.col), false)
+		diagnostic := l.diagnostics.Error(diagnostics.E_INVALID_CHARACTER, l.span(l.width(currentChar)),
+			"invalid character `%c` (%U) in source", rune, rune).
+			WithLabel("not allowed outside of comments and literals")
+		if isIdentifierStart(rune) && !l.unicode_ids {
+			diagnostic.WithNote("identifiers are ASCII only unless unicode identifiers are enabled")
+		}
+		l.col += l.width(currentChar)
 		return true, pos + size
 	} else {
 		// Single byte ASCII character (32-126)
 		token := createToken(T_UNKNOWN, currentChar, l.row, l.col)
-		l.token_stream = append(l.token_stream, token)
-		l.debug.DebugLog(fmt.Sprintf("Unknown token: %c at %d:%d", rune, l.row, l.col), true)
+		l.addToken(token, pos)
+		l.debug.DebugLog(fmt.Sprintf("Unknown token: %c at %d:%d", rune, l.row, l.col), false)
+		l.diagnostics.Error(diagnostics.E_UNKNOWN_CHARACTER, l.span(1),
+			"unknown character `%c`", rune).
+			WithLabel("not the start of any token")
 		l.col++
 		return true, pos + size
 	}
 }
+
+// handleUnicodeIdentifier handles identifiers with non ASCII characters
+// only when unicode identifiers are enabled, identifiers that are all
+// ASCII are left to the scanner so keywords are still found
+func (l *Lexer) handleUnicodeIdentifier(content string, pos int) (bool, int) {
+	if !l.unicode_ids {
+		return false, pos
+	}
+	r, size := utf8.DecodeRuneInString(content[pos:])
+	if !isIdentifierStart(r) {
+		return false, pos
+	}
+
+	end := pos + size
+	ascii := r < utf8.RuneSelf
+	for end < len(content) {
+		r, size := utf8.DecodeRuneInString(content[end:])
+		if !isIdentifierContinue(r) {
+			break
+		}
+		ascii = ascii && r < utf8.RuneSelf
+		end += size
+	}
+	if ascii {
+		return false, pos
+	}
+
+	identifierText := content[pos:end]
+	token := createToken(T_IDENTIFIER, identifierText, l.row, l.col)
+	l.addToken(token, pos)
+	l.debug.DebugLog(fmt.Sprintf("Unicode identifier: %s at %d:%d", identifierText, l.row, l.col), false)
+	l.col += l.width(identifierText)
+	return true, end
+}
+
+// checkUTF8 reports every byte of a comment or literal that is not valid UTF-8
+// the text starts at pos in the content, at the current row and column
+func (l *Lexer) checkUTF8(text string, pos int) {
+	if utf8.ValidString(text) {
+		return
+	}
+
+	span := l.span(1)
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r == utf8.RuneError && size == 1 {
+			l.diagnostics.Error(diagnostics.E_INVALID_UTF8, span,
+				"invalid UTF-8 byte 0x%02x at byte offset %d", text[i], l.offset+pos+i).
+				WithLabel("not valid UTF-8")
+		}
+		if r == '\n' {
+			span.Row++
+			span.Col = 1
+		} else {
+			span.Col += l.width(text[i : i+size])
+		}
+		i += size
+	}
+}
+
+// check if a character is a decimal digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// check if a character is a word character
+func isWordChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_'
+}
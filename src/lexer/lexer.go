@@ -6,31 +6,47 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"unicode/utf8"
 
 	debugger "github.com/CFdefense/compiler/src/debug"
+	"github.com/CFdefense/compiler/src/diagnostics"
 )
 
 // Lexer context object
 type Lexer struct {
-	token_stream []Token
-	content      map[string]string
-	row          int
-	col          int
-	debug        *debugger.Debug
-	tokenNFAs    []*NFA // store NFA tokens
-	tokenDFAs    []*DFA // store DFA tokens
+	token_stream         []Token
+	file_streams         map[string][]Token // each file's slice of the token stream
+	end_trivia           map[string]string  // whitespace of each file that has no token to hold it
+	content              map[string]string
+	file                 string // file currently being tokenized
+	offset               int    // byte offset of the content being tokenized within its file
+	row                  int
+	col                  int
+	columns              diagnostics.ColumnUnit // what col counts, runes unless set
+	unicode_ids          bool                   // identifiers may use XID_Start and XID_Continue characters
+	legacy_comments      bool                   // a // that starts a line is a comment, with a warning
+	string_interpolation bool                   // a string holding a { is lexed as an interpolated string
+	modes                []modeFrame            // every open context, innermost last
+	previous             Token                  // last token that is not a comment
+	first                int                    // index of the first token of the file being tokenized
+	trivia               []byte                 // whitespace read since the last token
+	debug                *debugger.Debug
+	diagnostics          *diagnostics.Reporter
+	scanner              Scanner // finds the longest token at a position, shared between lexers
 }
 
 // Lexer object constructor
 func InitializeLexer(debug bool) *Lexer {
 	return &Lexer{
 		token_stream: []Token{},
+		file_streams: make(map[string][]Token),
+		end_trivia:   make(map[string]string),
 		content:      make(map[string]string),
 		row:          1,
 		col:          1,
 		debug:        debugger.InitializeDebugger("LEX", debug),
-		tokenNFAs:    []*NFA{}, // initialize empty NFA slice
-		tokenDFAs:    []*DFA{}, // initialize empty DFA slice
+		diagnostics:  diagnostics.InitializeReporter(debug),
+		scanner:      GeneratedScannerTables(),
 	}
 }
 
@@ -61,7 +77,8 @@ func (l *Lexer) Scan(path string) {
 	files, err := os.ReadDir(path)
 
 	if err != nil {
-		l.debug.DebugLog(fmt.Sprintf("Failed to read directory: %v", err), true)
+		l.diagnostics.Error(diagnostics.E_UNREADABLE_SOURCE, diagnostics.Span{File: path},
+			"failed to read source directory: %v", err)
 		return
 	}
 
@@ -79,8 +96,9 @@ func (l *Lexer) Scan(path string) {
 
 		data, err := os.ReadFile(fullPath)
 		if err != nil {
-			l.debug.DebugLog(fmt.Sprintf("Error reading file: %v", err), true)
-			return
+			l.diagnostics.Error(diagnostics.E_UNREADABLE_SOURCE, diagnostics.Span{File: fileName},
+				"failed to read source file: %v", err)
+			continue
 		}
 
 		// add file name and contents to the content map
@@ -108,208 +126,182 @@ func (l *Lexer) Analyze() {
 	// 2. Convert Regex to NFA (Thompson's Algorithm)
 	// 3. Compute ε-Closure of States
 	// 4. Convert NFA to DFA
+	// 5. Minimize the DFA (Hopcroft's Algorithm)
 	//
 
 	// 1. define regex patterns (DONE)
 
-	// 2. convert regex to NFAs via thompson's algorithm
-	l.buildTokenNFAs()
-
-	// 3 & 4. Convert NFAs to DFAs
-	l.convertNFAsToDFAs()
+	// 2 - 5 are done ahead of time by go generate, which compiles the automata
+	// (see CompileAutomata) and stores the minimized DFA as the tables in
+	// scanner_tables.go, the lexer starts out scanning with those tables
 
-	// 5. Use DFAs for tokenization
+	// 6. Use the DFA for tokenization
 	l.tokenize()
 }
 
-// Thompson's algorithm implementation
-// 1. convert each token regex pattern to NFAs
-// 2. store NFAs in lexer struct for later use in steps 2, 3 ^^
-func (l *Lexer) buildTokenNFAs() {
-	l.debug.DebugLog("lexer: building NFAs", false)
-
-	// Define all our regex token definitions in priority order
-	regexDefs := []TokenRegexDef{
+// TokenRegexDefs lists every token definition of normal source in priority order
+// when two definitions match the same text the earlier one wins
+func TokenRegexDefs() []TokenRegexDef {
+	return []TokenRegexDef{
 		// Single-character operators first (highest priority)
-		{"PLUS", "^\\+", "", T_PLUS},
-		{"MINUS", "^-", "", T_MINUS},
-		{"MULTIPLY", "^\\*", "", T_MULTIPLY},
-		{"DIVIDE", "^/", "", T_DIVIDE},
-		{"MODULO", "^%", "", T_MODULO},
-		{"ASSIGN", "^=", "", T_ASSIGN},
-		{"LESS_THAN", "^<", "", T_LESS_THAN},
-		{"GREATER_THAN", "^>", "", T_GREATER_THAN},
-		{"NOT", "^!", "", T_NOT},
-		{"XOR", "^\\^", "", T_XOR},
-		{"AMPERSAND", "^&", "", T_AMPERSAND},
+		{"PLUS", "^\\+", T_PLUS},
+		{"MINUS", "^-", T_MINUS},
+		{"MULTIPLY", "^\\*", T_MULTIPLY},
+		{"DIVIDE", "^/", T_DIVIDE},
+		{"MODULO", "^%", T_MODULO},
+		{"ASSIGN", "^=", T_ASSIGN},
+		{"LESS_THAN", "^<", T_LESS_THAN},
+		{"GREATER_THAN", "^>", T_GREATER_THAN},
+		{"NOT", "^!", T_NOT},
+		{"XOR", "^\\^", T_XOR},
+		{"AMPERSAND", "^&", T_AMPERSAND},
+		{"PIPE", "^\\|", T_PIPE},
 
 		// Multi-character operators (longer patterns first for greedy matching)
-		{"EQUALS", "^==", "", T_EQUALS},
-		{"NOT_EQUALS", "^!=", "", T_NOT_EQUALS},
-		{"LESS_EQUAL", "^<=", "", T_LESS_EQUAL},
-		{"GREATER_EQUAL", "^>=", "", T_GREATER_EQUAL},
-		{"AND", "^&&", "", T_AND},
-		{"OR", "^\\|\\|", "", T_OR},
-		{"LEFT_SHIFT", "^<<", "", T_LEFT_SHIFT},
-		{"RIGHT_SHIFT", "^>>", "", T_RIGHT_SHIFT},
-		{"INT_DIVIDE", "^//", "", T_INT_DIVIDE},
-		{"ARROW", "^->", "", T_MEMBER_OPERATOR},
-		{"MATCH_ARROW", "^=>", "", T_ARROW},
+		{"EQUALS", "^==", T_EQUALS},
+		{"NOT_EQUALS", "^!=", T_NOT_EQUALS},
+		{"LESS_EQUAL", "^<=", T_LESS_EQUAL},
+		{"GREATER_EQUAL", "^>=", T_GREATER_EQUAL},
+		{"AND", "^&&", T_AND},
+		{"OR", "^\\|\\|", T_OR},
+		{"LEFT_SHIFT", "^<<", T_LEFT_SHIFT},
+		{"RIGHT_SHIFT", "^>>", T_RIGHT_SHIFT},
+		{"INT_DIVIDE", "^//", T_INT_DIVIDE},
+		{"ARROW", "^->", T_MEMBER_OPERATOR},
+		{"MATCH_ARROW", "^=>", T_ARROW},
 
 		// Boolean literals
-		{"BOOL", BOOL_PATTERN_STR, "", T_LITERAL},
+		{"BOOL", BOOL_PATTERN_STR, T_LITERAL},
 
 		// Numbers
-		{"NUMBER", NUMBER_PATTERN_STR, "", T_LITERAL},
+		{"NUMBER", NUMBER_PATTERN_STR, T_LITERAL},
 
 		// Underscore pattern for match arms
-		{"UNDERSCORE", "^_", "", T_UNDERSCORE},
+		{"UNDERSCORE", "^_", T_UNDERSCORE},
 		// Identifiers (before keywords to ensure full identifier matching)
-		{"IDENTIFIER", IDENTIFIER_PATTERN_STR, "", T_IDENTIFIER},
+		{"IDENTIFIER", IDENTIFIER_PATTERN_STR, T_IDENTIFIER},
 
 		// Keywords (individual patterns to avoid alternation issues) - AFTER identifiers
-		{"IF", "if", "", T_IF},
-		{"ELSE", "else", "", T_ELSE},
-		{"WHILE", "while", "", T_WHILE},
-		{"DO", "do", "", T_DO},
-		{"FOR", "for", "", T_FOR},
-		{"MATCH", "match", "", T_MATCH},
-		{"ENUM", "enum", "", T_ENUM},
-		{"STRUCT", "struct", "", T_STRUCT},
-		{"CONST", "const", "", T_CONST},
-		{"VOID", "void", "", T_VOID},
-		{"INT", "int", "", T_INT},
-		{"BOOL_KEYWORD", "bool", "", T_BOOL_KEYWORD},
-		{"MUT", "mut", "", T_MUT},
-		{"RETURN", "return", "", T_RETURN},
-		{"DEFAULT", "default", "", T_DEFAULT},
-		{"BREAK", "break", "", T_BREAK},
-		{"CONTINUE", "continue", "", T_CONTINUE},
-		{"SIZEOF", "sizeof", "", T_SIZEOF},
-		{"ASM", "asm", "", T_ASM},
+		{"IF", "if", T_IF},
+		{"ELSE", "else", T_ELSE},
+		{"WHILE", "while", T_WHILE},
+		{"DO", "do", T_DO},
+		{"FOR", "for", T_FOR},
+		{"MATCH", "match", T_MATCH},
+		{"ENUM", "enum", T_ENUM},
+		{"STRUCT", "struct", T_STRUCT},
+		{"CONST", "const", T_CONST},
+		{"VOID", "void", T_VOID},
+		{"INT", "int", T_INT},
+		{"BOOL_KEYWORD", "bool", T_BOOL_KEYWORD},
+		{"MUT", "mut", T_MUT},
+		{"RETURN", "return", T_RETURN},
+		{"DEFAULT", "default", T_DEFAULT},
+		{"BREAK", "break", T_BREAK},
+		{"CONTINUE", "continue", T_CONTINUE},
+		{"SIZEOF", "sizeof", T_SIZEOF},
+		{"ASM", "asm", T_ASM},
 
 		// Additional keywords
-		{"FUNCTION", "function", "", T_FUNCTION},
+		{"FUNCTION", "function", T_FUNCTION},
 
 		// Constants
-		{"CONSTANT", CONSTANT_PATTERN_STR, "", T_LITERAL},
+		{"CONSTANT", CONSTANT_PATTERN_STR, T_LITERAL},
 
 		// String literals (after identifiers)
-		{"STRING", STRING_PATTERN_STR, "", T_STRING_LITERAL},
-		{"CHAR", CHAR_PATTERN_STR, "", T_CHAR_LITERAL},
-		{"ESCAPE", ESCAPE_SEQUENCE_PATTERN_STR, "", T_ESCAPE_SEQUENCE},
+		{"STRING", STRING_PATTERN_STR, T_STRING_LITERAL},
+		{"CHAR", CHAR_PATTERN_STR, T_CHAR_LITERAL},
+		{"RAW_STRING", RAW_STRING_PATTERN_STR, T_RAW_STRING_LITERAL},
+		{"BYTE_STRING", BYTE_STRING_PATTERN_STR, T_BYTE_STRING_LITERAL},
+		{"BYTE_CHAR", BYTE_CHAR_PATTERN_STR, T_BYTE_CHAR_LITERAL},
+		{"ESCAPE", ESCAPE_SEQUENCE_PATTERN_STR, T_ESCAPE_SEQUENCE},
+		// a backslash that does not start a known escape sequence
+		{"BACKSLASH", "^\\\\", T_ESCAPE_SEQUENCE},
 
 		// Punctuators
-		{"PUNCTUATOR", PUNCTUATOR_PATTERN_STR, "", T_PUNCTUATOR},
+		{"PUNCTUATOR", PUNCTUATOR_PATTERN_STR, T_PUNCTUATOR},
 
 		// Special tokens
-		{"SPECIAL", SPECIAL_PATTERN_STR, "", T_SPECIAL},
-
-		// ASM patterns (temporarily disabled)
-		// {"ASM_INSTRUCTION", ASM_INSTRUCTION_STR, "", T_ASM_INSTRUCTION},
-		// {"ASM_REGISTER", ASM_REGISTER_STR, "", T_ASM_REGISTER},
-		// {"ASM_IMMEDIATE", ASM_IMMEDIATE_STR, "", T_ASM_IMMEDIATE},
-		// {"ASM_MEMORY_REF", ASM_MEMORY_REF_STR, "", T_ASM_MEMORY_REF},
-		// {"ASM_LABEL", ASM_LABEL_STR, "", T_ASM_LABEL},
-	}
+		{"SPECIAL", SPECIAL_PATTERN_STR, T_SPECIAL},
 
-	// Build NFAs for each token
-	l.tokenNFAs = make([]*NFA, 0, len(regexDefs))
-	for i, regexDef := range regexDefs {
-		regexDefs[i].Postfix = postfix(regexDef.Pattern, regexDef.Name, l.debug)
-		l.debug.DebugLog(fmt.Sprintf("Pattern: %s -> Postfix: %s", regexDef.Pattern, regexDefs[i].Postfix), false)
-		if regexDef.Name == "STRING" {
-			tokens := tokenizeRegex(regexDef.Pattern)
-			l.debug.DebugLog(fmt.Sprintf("STRING pattern tokens: %v", tokens), false)
-			for j, t := range tokens {
-				l.debug.DebugLog(fmt.Sprintf("  Token %d: %s (%s)", j, t.Value, t.Type), false)
-			}
-		}
-		nfa := thompsonConstruct(regexDefs[i].Postfix, regexDef.TokenType)
-		nfa.Print(l.debug)
-		l.testNFA(nfa, regexDef.Name, regexDef.Pattern)
-		l.tokenNFAs = append(l.tokenNFAs, nfa)
-		l.debug.DebugLog(fmt.Sprintf("Added NFA %d for pattern %s with token type %s", len(l.tokenNFAs)-1, regexDef.Name, regexDef.TokenType.String()), false)
+		// asm blocks and interpolated strings have definitions of their own, see ModeRegexDefs
 	}
-
-	l.debug.DebugLog("lexer: success on NFAs", false)
 }
 
-// Convert NFAs to DFAs using the subset construction algorithm
-func (l *Lexer) convertNFAsToDFAs() {
-	l.debug.DebugLog("lexer: converting NFAs to DFAs", false)
-
-	l.tokenDFAs = []*DFA{} // reset DFAs
+// Tokenize the input using the scanner DFA
+func (l *Lexer) tokenize() {
+	l.debug.DebugLog("lexer: starting tokenization", false)
 
-	// Convert each NFA to a DFA
-	for i, nfa := range l.tokenNFAs {
-		tokenTypeName := "unknown"
-		if nfa.end != nil && nfa.end.tokenType != 0 {
-			tokenTypeName = nfa.end.tokenType.String()
+	// map order is random, files are always tokenized in sorted order
+	// so the combined token stream is the same on every run
+	for _, filename := range l.GetFiles() {
+		content := l.content[filename]
+		l.debug.DebugLog(fmt.Sprintf("Tokenizing file: %s", filename), false)
+		l.diagnostics.AddSource(filename, content)
+		l.file = filename
+		l.offset = 0
+		l.row = 1
+		l.col = 1
+		l.modes = nil
+		l.previous = Token{}
+		first := len(l.token_stream)
+		l.first = first
+		for pos := 0; pos < len(content); {
+			pos = l.step(content, pos)
 		}
-
-		l.debug.DebugLog(fmt.Sprintf("Converting NFA %d to DFA for %s", i, tokenTypeName), false)
-
-		// Add timeout and error handling
-		dfa := ConvertNFAtoDFA(nfa)
-		if dfa == nil {
-			l.debug.DebugLog(fmt.Sprintf("Failed to convert NFA %d to DFA, using fallback", i), true)
-			// Create a simple fallback DFA
-			dfa = &DFA{
-				start:    &DFAState{id: generateDFAStateID(), isAccepting: true, tokenType: nfa.end.tokenType},
-				states:   []*DFAState{},
-				alphabet: make(map[string]bool),
-			}
+		if l.inString() {
+			l.handleUnterminatedString()
 		}
+		l.finishTrivia()
 
-		l.tokenDFAs = append(l.tokenDFAs, dfa)
-
-		// Debug output - only print small DFAs to avoid log flooding
-		if len(dfa.states) < 50 {
-			l.debug.DebugLog(dfa.PrintDFA(), false)
-		} else {
-			l.debug.DebugLog(fmt.Sprintf("DFA %d has %d states (too large to print)", i, len(dfa.states)), false)
-		}
+		// capacity is clipped so later files can never append into this slice
+		last := len(l.token_stream)
+		l.file_streams[filename] = l.token_stream[first:last:last]
 	}
-
-	l.debug.DebugLog(fmt.Sprintf("lexer: successfully converted %d NFAs to DFAs", len(l.tokenDFAs)), false)
 }
 
-// Tokenize the input using the DFAs
-func (l *Lexer) tokenize() {
-	l.debug.DebugLog("lexer: starting tokenization", false)
-
-	for filename, content := range l.content {
-		l.debug.DebugLog(fmt.Sprintf("Tokenizing file: %s", filename), false)
-		l.row = 1
-		l.col = 1
-		pos := 0
-		for pos < len(content) {
-			if handled, newPos := l.handleWhitespace(content, pos); handled {
-				pos = newPos
-				continue
-			}
-			if handled, newPos := l.handleSingleLineComment(content, pos); handled {
-				pos = newPos
-				continue
-			}
-			if handled, newPos := l.handleMultiLineComment(content, pos); handled {
-				pos = newPos
-				continue
-			}
-			if handled, newPos := l.handleOperator(content, pos); handled {
-				pos = newPos
-				continue
-			}
-			// Fallback: DFA-based matching and unknowns
-			if handled, newPos := l.handleDfaOrUnknown(content, pos); handled {
-				pos = newPos
-				continue
-			}
-			// If nothing handled, move forward to avoid infinite loop
-			pos++
+// step lexes the token(s) at pos and returns the position after them
+// the handlers are tried in order, the first one to match wins
+func (l *Lexer) step(content string, pos int) int {
+	// the text of an interpolated string is lexed by its own definitions alone
+	// and like any string it ends by the end of its line
+	if l.inString() && (content[pos] == '\n' || content[pos] == '\r') {
+		l.handleUnterminatedString()
+	} else if l.mode() == MODE_STRING {
+		if handled, newPos := l.handleMode(content, pos); handled {
+			return newPos
 		}
 	}
+	if handled, newPos := l.handleWhitespace(content, pos); handled {
+		return newPos
+	}
+	if handled, newPos := l.handleSingleLineComment(content, pos); handled {
+		return newPos
+	}
+	if handled, newPos := l.handleSingleLineDocComment(content, pos); handled {
+		return newPos
+	}
+	if handled, newPos := l.handleMultiLineComment(content, pos); handled {
+		return newPos
+	}
+	if handled, newPos := l.handlePrefixedLiteral(content, pos); handled {
+		return newPos
+	}
+	if handled, newPos := l.handleMode(content, pos); handled {
+		return newPos
+	}
+	if handled, newPos := l.handleUnicodeIdentifier(content, pos); handled {
+		return newPos
+	}
+	if handled, newPos := l.handleOperator(content, pos); handled {
+		return newPos
+	}
+	// Fallback: DFA-based matching and unknowns
+	if handled, newPos := l.handleDfaOrUnknown(content, pos); handled {
+		return newPos
+	}
+	// If nothing handled, move forward to avoid infinite loop
+	return pos + 1
 }
 
 // Function to reset a lexer
@@ -317,11 +309,18 @@ func (l *Lexer) tokenize() {
 // can also be used in between compiling multiple files
 func (l *Lexer) ResetLexer() {
 	l.token_stream = []Token{}
+	l.file_streams = make(map[string][]Token)
+	l.end_trivia = make(map[string]string)
 	l.content = make(map[string]string)
+	l.file = ""
 	l.row = 1
 	l.col = 1
-	l.tokenNFAs = []*NFA{} // reset NFAs
-	l.tokenDFAs = []*DFA{} // reset DFAs
+	l.modes = nil
+	l.previous = Token{}
+	l.first = 0
+	l.trivia = nil
+	l.diagnostics.ResetReporter()
+	// the scanner is shared and immutable so it is kept
 }
 
 // Function to get the token stream
@@ -329,33 +328,132 @@ func (l *Lexer) GetTokenStream() []Token {
 	return l.token_stream
 }
 
+// Function to set the scanner used to find tokens
+// CompiledAutomata().GetScanner() scans with the automata built at runtime
+func (l *Lexer) SetScanner(scanner Scanner) {
+	l.scanner = scanner
+}
+
+// Function to get the source file names in compilation order
+func (l *Lexer) GetFiles() []string {
+	files := make([]string, 0, len(l.content))
+	for file := range l.content {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Function to get the tokens of a single source file
+// returns nil if the file was not tokenized
+func (l *Lexer) GetFileTokenStream(file string) []Token {
+	return l.file_streams[file]
+}
+
+// Function to get the whitespace of a file that has no tokens, split into pieces
+// it is empty for every other file, their tokens hold all of their whitespace
+func (l *Lexer) GetEndTrivia(file string) []Trivia {
+	return splitTrivia(l.end_trivia[file])
+}
+
+// Function to get the token stream of every source file keyed by file name
+func (l *Lexer) GetFileTokenStreams() map[string][]Token {
+	return l.file_streams
+}
+
+// Function to get the diagnostics reported while lexing
+func (l *Lexer) GetReporter() *diagnostics.Reporter {
+	return l.diagnostics
+}
+
+// Function to share a diagnostics reporter with the other compiler phases
+// the reporter takes on the lexer's column unit
+func (l *Lexer) SetReporter(reporter *diagnostics.Reporter) {
+	l.diagnostics = reporter
+	l.diagnostics.SetColumnUnit(l.columns)
+}
+
+// Function to set what token columns count, runes unless set
+// diagnostics are reported in the same unit
+func (l *Lexer) SetColumnUnit(unit diagnostics.ColumnUnit) {
+	l.columns = unit
+	l.diagnostics.SetColumnUnit(unit)
+}
+
+// Function to get what token columns count
+func (l *Lexer) GetColumnUnit() diagnostics.ColumnUnit {
+	return l.columns
+}
+
+// Function to allow identifiers made of any XID_Start and XID_Continue characters
+// off by default, non ASCII characters are then only allowed in comments and literals
+func (l *Lexer) SetUnicodeIdentifiers(enabled bool) {
+	l.unicode_ids = enabled
+}
+
+// Function to check if identifiers may use non ASCII characters
+func (l *Lexer) GetUnicodeIdentifiers() bool {
+	return l.unicode_ids
+}
+
+// Function to read a // that starts a line as a comment, as older Sea code wrote them
+// off by default, every such comment is warned about so it can be changed to #
+func (l *Lexer) SetLegacyComments(enabled bool) {
+	l.legacy_comments = enabled
+}
+
+// Function to check if // comments are read at the start of a line
+func (l *Lexer) GetLegacyComments() bool {
+	return l.legacy_comments
+}
+
+// Function to lex a string holding a { as an interpolated string, as in "x = {x}"
+// off by default, the parser does not read interpolated strings yet
+func (l *Lexer) SetStringInterpolation(enabled bool) {
+	l.string_interpolation = enabled
+}
+
+// Function to check if strings holding a { are lexed as interpolated strings
+func (l *Lexer) GetStringInterpolation() bool {
+	return l.string_interpolation
+}
+
+// span builds a diagnostic span at the current lexer position
+func (l *Lexer) span(length int) diagnostics.Span {
+	return diagnostics.Span{File: l.file, Row: l.row, Col: l.col, Length: length}
+}
+
+// Function to get the source content of every file by file name
+func (l *Lexer) GetContent() map[string]string {
+	return l.content
+}
+
 // Function to set the content of the lexer
 func (l *Lexer) SetContent(content map[string]string) {
 	l.content = content
 }
 
-func (l *Lexer) testNFA(nfa *NFA, name, pattern string) {
-	// TODO: Not sure if we need this testing functionality if converting to DFA but wrote logic for it anyways
-}
-
+// updatePosition moves the row and column past some text
 func (l *Lexer) updatePosition(text string) {
-	for _, c := range text {
-		if c == '\n' {
-			l.row++
-			l.col = 1
-		} else {
-			l.col++
+	for {
+		line := strings.IndexByte(text, '\n')
+		if line == -1 {
+			break
 		}
+		l.row++
+		l.col = 1
+		text = text[line+1:]
 	}
+	l.col += l.width(text)
 }
 
-func isAllDigits(text string) bool {
-	for _, c := range text {
-		if c < '0' || c > '9' {
-			return false
-		}
+// width is how many columns some text on one line takes up
+// an invalid UTF-8 byte takes one column
+func (l *Lexer) width(text string) int {
+	if l.columns == diagnostics.COLUMN_BYTES {
+		return len(text)
 	}
-	return len(text) > 0
+	return utf8.RuneCountInString(text)
 }
 
 // TestLexerWithInput tests the lexer with a simple input string
@@ -365,9 +463,10 @@ func (l *Lexer) TestLexerWithInput(input string) {
 	// Set the content
 	l.content = map[string]string{"test.txt": input}
 
-	// Run the full lexer pipeline
-	l.buildTokenNFAs()
-	l.convertNFAsToDFAs()
+	// Run the full lexer pipeline on the scanner built at runtime
+	// the lexer goes back to its own scanner afterwards
+	defer l.SetScanner(l.scanner)
+	l.scanner = CompiledAutomata().GetScanner()
 	l.tokenize()
 
 	// Print results
@@ -379,97 +478,11 @@ func (l *Lexer) TestLexerWithInput(input string) {
 }
 
 func (l *Lexer) handleDfaOrUnknown(content string, pos int) (bool, int) {
-	// Try to match a token starting at the current position
-	matched := false
-	maxMatchLength := 0
-	var matchedTokenType TokenType
-
-	// Try each DFA and find the longest match
-	for dfaIndex, dfa := range l.tokenDFAs {
-		// Try to match as much as possible from the current position
-		i := pos
-		currentState := dfa.start
-		lastAcceptingPos := -1
-		var lastAcceptingType TokenType
-
-		for i <= len(content) {
-			// Check if current state is accepting
-			if currentState.isAccepting {
-				lastAcceptingPos = i
-				lastAcceptingType = currentState.tokenType
-			}
-
-			if i >= len(content) {
-				break
-			}
-
-			// Try to find a transition
-			found := false
-			var nextState *DFAState
-
-			// First, try multi-character patterns (for operators and keywords) deterministically by length desc
-			if len(currentState.transitions) > 0 {
-				multi := make([]string, 0, len(currentState.transitions))
-				for k := range currentState.transitions {
-					if k != "any" && len(k) > 1 {
-						multi = append(multi, k)
-					}
-				}
-				sort.Slice(multi, func(a, b int) bool { return len(multi[a]) > len(multi[b]) })
-				for _, transitionSymbol := range multi {
-					if i+len(transitionSymbol) <= len(content) && content[i:i+len(transitionSymbol)] == transitionSymbol {
-						nextState = currentState.transitions[transitionSymbol]
-						found = true
-						i += len(transitionSymbol)
-						break
-					}
-				}
-			}
-
-			if !found {
-				// Then try exact single character match
-				symbol := string(content[i])
-				if transition, exists := currentState.transitions[symbol]; exists {
-					nextState = transition
-					found = true
-					i++
-				}
-			}
-
-			if !found {
-				// Try "any" transition as fallback
-				if transition, exists := currentState.transitions["any"]; exists {
-					nextState = transition
-					i++
-				} else {
-					break // No valid transition
-				}
-			}
-
-			// Safety check to prevent infinite loops
-			if nextState == nil {
-				break
-			}
-
-			currentState = nextState
-		}
-
-		// Check final state
-		if currentState.isAccepting && (lastAcceptingPos == -1 || i > lastAcceptingPos) {
-			lastAcceptingPos = i
-			lastAcceptingType = currentState.tokenType
-		}
-
-		if lastAcceptingPos > pos {
-			// Found a match, check if it's longer than previous matches
-			matchLength := lastAcceptingPos - pos
-			if matchLength > maxMatchLength {
-				maxMatchLength = matchLength
-				matchedTokenType = lastAcceptingType
-				matched = true
-				l.debug.DebugLog(fmt.Sprintf("New longest match: DFA %d matched token type %s (length %d)", dfaIndex, matchedTokenType.String(), matchLength), false)
-			}
-		}
+	// Find the longest token starting at the current position in one walk of the scanner
+	maxMatchLength, matchedTokenType := l.scanner.Match(content, pos)
+	matched := maxMatchLength > 0
+	if matched {
+		l.debug.DebugLog(fmt.Sprintf("Longest match: token type %s (length %d)", matchedTokenType.String(), maxMatchLength), false)
 	}
 
 	if matched {
@@ -504,19 +517,20 @@ func (l *Lexer) handleDfaOrUnknown(content string, pos int) (bool, int) {
 						row:        l.row,
 						col:        l.col,
 					}
-					l.token_stream = append(l.token_stream, identifierToken)
+					l.addToken(identifierToken, pos)
 
 					// Create operator tokens
+					operatorStart := pos + len(identifierText)
 					if operatorText == "++" {
 						op1 := createToken(T_PLUS, "+", l.row, l.col+len(identifierText))
-						l.token_stream = append(l.token_stream, op1)
+						l.addToken(op1, operatorStart)
 						op2 := createToken(T_PLUS, "+", l.row, l.col+len(identifierText)+1)
-						l.token_stream = append(l.token_stream, op2)
+						l.addToken(op2, operatorStart+1)
 					} else { // "--"
 						op1 := createToken(T_MINUS, "-", l.row, l.col+len(identifierText))
-						l.token_stream = append(l.token_stream, op1)
+						l.addToken(op1, operatorStart)
 						op2 := createToken(T_MINUS, "-", l.row, l.col+len(identifierText)+1)
-						l.token_stream = append(l.token_stream, op2)
+						l.addToken(op2, operatorStart+1)
 					}
 
 					// Update position
@@ -559,7 +573,7 @@ func (l *Lexer) handleDfaOrUnknown(content string, pos int) (bool, int) {
 						row:        l.row,
 						col:        l.col,
 					}
-					l.token_stream = append(l.token_stream, numberToken)
+					l.addToken(numberToken, pos)
 
 					// Create identifier token
 					identifierToken := Token{
@@ -568,7 +582,7 @@ func (l *Lexer) handleDfaOrUnknown(content string, pos int) (bool, int) {
 						row:        l.row,
 						col:        l.col + len(numberText),
 					}
-					l.token_stream = append(l.token_stream, identifierToken)
+					l.addToken(identifierToken, pos+numberEnd)
 
 					// Update position
 					l.updatePosition(tokenText)
@@ -583,18 +597,9 @@ func (l *Lexer) handleDfaOrUnknown(content string, pos int) (bool, int) {
 			// Map generic literals to specific types
 			if tokenText == "true" || tokenText == "false" {
 				tokenType = T_BOOL_LITERAL
-			} else if isNumeric(tokenText) {
-				// Check if this is an oversized decimal integer
-				if len(tokenText) > 19 && tokenText[0] >= '1' && tokenText[0] <= '9' {
-					// Check if it's all digits (decimal number)
-					if isAllDigits(tokenText) {
-						tokenType = T_ERROR
-					} else {
-						tokenType = T_INT_LITERAL
-					}
-				} else {
-					tokenType = T_INT_LITERAL
-				}
+			} else if isDigit(tokenText[0]) {
+				// decoding the value reports numbers that are malformed or too large
+				tokenType = numberTokenType(tokenText)
 			} else {
 				tokenType = T_LITERAL // Keep as generic literal for other cases
 			}
@@ -606,8 +611,8 @@ func (l *Lexer) handleDfaOrUnknown(content string, pos int) (bool, int) {
 
 		// Map T_LITERAL to T_IDENTIFIER unless it's a number or boolean
 		if tokenType == T_LITERAL {
-			if isNumeric(tokenText) {
-				tokenType = T_INT_LITERAL
+			if isDigit(tokenText[0]) {
+				tokenType = numberTokenType(tokenText)
 			} else if tokenText == "true" || tokenText == "false" {
 				tokenType = T_BOOL_LITERAL
 			} else {
@@ -626,7 +631,7 @@ func (l *Lexer) handleDfaOrUnknown(content string, pos int) (bool, int) {
 			col:        l.col,
 		}
 
-		l.token_stream = append(l.token_stream, token)
+		l.addToken(token, pos)
 		l.debug.DebugLog(fmt.Sprintf("Token: %s (%s) at %d:%d", tokenText, tokenType.String(), l.row, l.col), false)
 
 		// Update position and column
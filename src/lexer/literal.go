@@ -0,0 +1,371 @@
+package lexer
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/CFdefense/compiler/src/diagnostics"
+)
+
+// What kind of value a literal token holds
+type LiteralKind int
+
+const (
+	LITERAL_INT LiteralKind = iota
+	LITERAL_BOOL
+	LITERAL_STRING
+	LITERAL_CHAR
+	LITERAL_FLOAT
+	LITERAL_BYTES
+)
+
+// LiteralValue is the decoded value of a literal token
+// numbers are kept exactly, integers to every digit and floats as the fraction
+// they were written as, rounding to a type is left to whoever uses them
+type LiteralValue struct {
+	kind    LiteralKind
+	integer *big.Int
+	float   *big.Rat
+	radix   int    // base the number was written in, 2, 8, 10 or 16
+	suffix  string // type suffix of a number such as u8 or f32, empty if none
+	text    string // string literal with its escapes interpreted, raw bytes for a byte string
+	char    rune
+	boolean bool
+}
+
+func (v *LiteralValue) GetKind() LiteralKind {
+	return v.kind
+}
+
+// Function to get the value of an integer literal
+// returns a copy so the token's value cannot be changed through it
+func (v *LiteralValue) GetInt() *big.Int {
+	if v.integer == nil {
+		return nil
+	}
+	return new(big.Int).Set(v.integer)
+}
+
+// Function to get the exact value of a float literal
+// returns a copy so the token's value cannot be changed through it
+func (v *LiteralValue) GetFloat() *big.Rat {
+	if v.float == nil {
+		return nil
+	}
+	return new(big.Rat).Set(v.float)
+}
+
+func (v *LiteralValue) GetRadix() int {
+	return v.radix
+}
+
+func (v *LiteralValue) GetSuffix() string {
+	return v.suffix
+}
+
+func (v *LiteralValue) GetString() string {
+	return v.text
+}
+
+func (v *LiteralValue) GetChar() rune {
+	return v.char
+}
+
+func (v *LiteralValue) GetBool() bool {
+	return v.boolean
+}
+
+// String shows the value the way it would be written without a prefix, suffix or quotes
+// integers are shown in decimal, floats rounded to the closest value of their type
+// and bytes outside of printable ASCII as \xHH
+func (v *LiteralValue) String() string {
+	switch v.kind {
+	case LITERAL_INT:
+		return v.integer.String()
+	case LITERAL_FLOAT:
+		if v.suffix == "f32" {
+			f, _ := v.float.Float32()
+			return strconv.FormatFloat(float64(f), 'g', -1, 32)
+		}
+		f, _ := v.float.Float64()
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	case LITERAL_BOOL:
+		return strconv.FormatBool(v.boolean)
+	case LITERAL_CHAR:
+		return string(v.char)
+	case LITERAL_BYTES:
+		var shown strings.Builder
+		for i := 0; i < len(v.text); i++ {
+			if c := v.text[i]; c >= ' ' && c <= '~' {
+				shown.WriteByte(c)
+			} else {
+				fmt.Fprintf(&shown, "\\x%02x", c)
+			}
+		}
+		return shown.String()
+	default:
+		return v.text
+	}
+}
+
+// Equal checks if two values are the same, either may be nil
+func (v *LiteralValue) Equal(other *LiteralValue) bool {
+	if v == nil || other == nil {
+		return v == other
+	}
+	if v.kind != other.kind || v.radix != other.radix || v.suffix != other.suffix ||
+		v.text != other.text || v.char != other.char || v.boolean != other.boolean {
+		return false
+	}
+	if (v.integer == nil) != (other.integer == nil) || (v.float == nil) != (other.float == nil) {
+		return false
+	}
+	return (v.integer == nil || v.integer.Cmp(other.integer) == 0) &&
+		(v.float == nil || v.float.Cmp(other.float) == 0)
+}
+
+// decodeLiteral gives a literal token its value
+// problems are reported, a malformed or out of range number turns the token into T_ERROR
+func (l *Lexer) decodeLiteral(token *Token) {
+	switch token.token_type {
+	case T_INT_LITERAL, T_FLOAT_LITERAL:
+		l.decodeNumber(token)
+	case T_BOOL_LITERAL:
+		token.value = &LiteralValue{kind: LITERAL_BOOL, boolean: token.lexeme == "true"}
+	case T_STRING_LITERAL:
+		text := l.decodeEscapes(*token, 1, false)
+		token.value = &LiteralValue{kind: LITERAL_STRING, text: text}
+	case T_STRING_FRAGMENT:
+		// a fragment has no quotes, the closing quote is put back for decodeEscapes
+		fragment := *token
+		fragment.lexeme += `"`
+		text := l.decodeEscapes(fragment, 0, false)
+		token.value = &LiteralValue{kind: LITERAL_STRING, text: text}
+	case T_RAW_STRING_LITERAL:
+		// r, the # and the quote open the string and the quote and # close it
+		hashes := len(token.lexeme) - len(strings.TrimLeft(token.lexeme[1:], "#")) - 1
+		text := token.lexeme[2+hashes : len(token.lexeme)-1-hashes]
+		token.value = &LiteralValue{kind: LITERAL_STRING, text: text}
+	case T_BYTE_STRING_LITERAL:
+		text := l.decodeEscapes(*token, 2, true)
+		token.value = &LiteralValue{kind: LITERAL_BYTES, text: text}
+	case T_CHAR_LITERAL:
+		text := l.decodeEscapes(*token, 1, false)
+		value := &LiteralValue{kind: LITERAL_CHAR}
+		value.char, _ = utf8.DecodeRuneInString(text)
+		token.value = value
+		switch count := utf8.RuneCountInString(text); {
+		case count == 0:
+			l.diagnostics.Error(diagnostics.E_INVALID_CHAR_LITERAL, l.spanAt(*token, 0, l.width(token.lexeme)),
+				"empty character literal").
+				WithLabel("needs a character between the quotes")
+		case count > 1:
+			l.diagnostics.Error(diagnostics.E_INVALID_CHAR_LITERAL, l.spanAt(*token, 0, l.width(token.lexeme)),
+				"character literal may only contain one character").
+				WithLabel(fmt.Sprintf("holds %d characters", count)).
+				WithNote("use double quotes for a string")
+		}
+	case T_BYTE_CHAR_LITERAL:
+		// a byte character is the u8 it stands for
+		text := l.decodeEscapes(*token, 2, true)
+		value := &LiteralValue{kind: LITERAL_INT, integer: new(big.Int), suffix: "u8"}
+		if len(text) > 0 {
+			value.integer.SetInt64(int64(text[0]))
+		}
+		token.value = value
+		switch {
+		case len(text) == 0:
+			l.diagnostics.Error(diagnostics.E_INVALID_CHAR_LITERAL, l.spanAt(*token, 0, l.width(token.lexeme)),
+				"empty byte literal").
+				WithLabel("needs a byte between the quotes")
+		case utf8.RuneCountInString(text) > 1:
+			l.diagnostics.Error(diagnostics.E_INVALID_CHAR_LITERAL, l.spanAt(*token, 0, l.width(token.lexeme)),
+				"byte literal may only contain one byte").
+				WithLabel(fmt.Sprintf("holds %d bytes", len(text))).
+				WithNote("use `b\"...\"` for a byte string")
+		}
+	}
+}
+
+// decodeEscapes interprets the escape sequences of a literal
+// open is the length of the prefix and quote before the text
+// a byte literal holds bytes, so \xHH may go up to \xff but \u{..} and
+// characters outside of ASCII are not allowed
+// a bad escape is reported and kept as the character after the backslash
+func (l *Lexer) decodeEscapes(token Token, open int, bytes bool) string {
+	text := token.lexeme
+	if len(text) < open+1 {
+		return ""
+	}
+	inner := text[:len(text)-1]
+	if !strings.Contains(inner[open:], "\\") && (!bytes || isASCII(inner[open:])) {
+		return inner[open:]
+	}
+
+	var decoded strings.Builder
+	for i := open; i < len(inner); {
+		if inner[i] != '\\' {
+			r, size := utf8.DecodeRuneInString(inner[i:])
+			if bytes && r >= utf8.RuneSelf && size > 1 {
+				l.nonASCIIByte(token, i, inner[i:i+size])
+			}
+			decoded.WriteString(inner[i : i+size])
+			i += size
+			continue
+		}
+		r, size := l.decodeEscape(token, inner, i, bytes)
+		switch {
+		case r < 0:
+		case bytes && r <= 0xff:
+			decoded.WriteByte(byte(r))
+		default:
+			decoded.WriteRune(r)
+		}
+		i += size
+	}
+	return decoded.String()
+}
+
+// nonASCIIByte reports a character of a byte literal that is not ASCII
+// i is its byte offset into the lexeme
+func (l *Lexer) nonASCIIByte(token Token, i int, char string) {
+	var escaped strings.Builder
+	for j := 0; j < len(char); j++ {
+		fmt.Fprintf(&escaped, "\\x%02x", char[j])
+	}
+	l.diagnostics.Error(diagnostics.E_NON_ASCII_BYTE, l.spanAt(token, i, l.width(char)),
+		"non-ASCII character `%s` in byte literal", char).
+		WithLabel("byte literals may only hold ASCII").
+		WithNote(fmt.Sprintf("write its UTF-8 bytes as `%s`", escaped.String()))
+}
+
+// decodeEscape decodes the escape sequence starting at the backslash at i
+// i is a byte offset into the lexeme, inner is the lexeme without its closing quote
+// returns the character and the length of the sequence, -1 for a line continuation
+func (l *Lexer) decodeEscape(token Token, inner string, i int, bytes bool) (rune, int) {
+	if i+1 >= len(inner) {
+		return '\\', 1
+	}
+
+	switch inner[i+1] {
+	case 'n':
+		return '\n', 2
+	case 't':
+		return '\t', 2
+	case 'r':
+		return '\r', 2
+	case '0':
+		return 0, 2
+	case '\\', '"', '\'', '{', '}':
+		return rune(inner[i+1]), 2
+	case '\n':
+		// a backslash at the end of a line joins it to the next one
+		return -1, 2
+	case '\r':
+		if i+2 < len(inner) && inner[i+2] == '\n' {
+			return -1, 3
+		}
+		return -1, 2
+	case 'x':
+		digits := inner[i+2 : min(i+4, len(inner))]
+		value, err := strconv.ParseUint(digits, 16, 8)
+		if len(digits) < 2 || err != nil {
+			l.diagnostics.Error(diagnostics.E_INVALID_ESCAPE, l.spanAt(token, i, 2),
+				"`\\x` escape needs two hex digits").
+				WithLabel("expected two hex digits after `\\x`")
+			return 'x', 2
+		}
+		if value > 0x7f && !bytes {
+			l.diagnostics.Error(diagnostics.E_INVALID_ESCAPE, l.spanAt(token, i, 4),
+				"`\\x%s` is out of range", digits).
+				WithLabel("must be at most `\\x7f`").
+				WithNote(fmt.Sprintf("use `\\u{%x}` for the character U+%04X", value, value))
+			return utf8.RuneError, 4
+		}
+		return rune(value), 4
+	case 'u':
+		if bytes {
+			l.diagnostics.Error(diagnostics.E_INVALID_ESCAPE, l.spanAt(token, i, 2),
+				"unicode escape in byte literal").
+				WithLabel("byte literals cannot hold unicode escapes").
+				WithNote("write the character's UTF-8 bytes with `\\xHH` escapes")
+			size := 2
+			if end := strings.IndexByte(inner[i+2:], '}'); i+2 < len(inner) && inner[i+2] == '{' && end != -1 {
+				size = 2 + end + 1
+			}
+			return -1, size
+		}
+		return l.decodeUnicodeEscape(token, inner, i)
+	}
+
+	r, size := utf8.DecodeRuneInString(inner[i+1:])
+	l.diagnostics.Error(diagnostics.E_INVALID_ESCAPE, l.spanAt(token, i, 1+l.width(inner[i+1:i+1+size])),
+		"unknown escape sequence `\\%c`", r).
+		WithLabel("not a known escape").
+		WithNote("known escapes are \\n \\t \\r \\0 \\\\ \\\" \\' \\xHH and \\u{HHHH}")
+	return r, 1 + size
+}
+
+// decodeUnicodeEscape decodes a \u{HHHH} escape starting at the backslash at i
+// one to six hex digits naming any character but a surrogate
+func (l *Lexer) decodeUnicodeEscape(token Token, inner string, i int) (rune, int) {
+	if i+2 >= len(inner) || inner[i+2] != '{' {
+		l.diagnostics.Error(diagnostics.E_INVALID_ESCAPE, l.spanAt(token, i, 2),
+			"`\\u` escape needs braces").
+			WithLabel("expected `{` after `\\u`").
+			WithNote("write the character as `\\u{HHHH}`")
+		return 'u', 2
+	}
+	end := strings.IndexByte(inner[i+3:], '}')
+	if end == -1 {
+		l.diagnostics.Error(diagnostics.E_INVALID_ESCAPE, l.spanAt(token, i, 2),
+			"unterminated unicode escape").
+			WithLabel("missing closing `}`")
+		return 'u', 2
+	}
+
+	size := 3 + end + 1
+	digits := inner[i+3 : i+3+end]
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if len(digits) == 0 || len(digits) > 6 || err != nil {
+		l.diagnostics.Error(diagnostics.E_INVALID_ESCAPE, l.spanAt(token, i, l.width(inner[i:i+size])),
+			"invalid unicode escape `\\u{%s}`", digits).
+			WithLabel("expected one to six hex digits")
+		return utf8.RuneError, size
+	}
+	if !utf8.ValidRune(rune(value)) {
+		l.diagnostics.Error(diagnostics.E_INVALID_ESCAPE, l.spanAt(token, i, size),
+			"unicode escape `\\u{%s}` is out of range", digits).
+			WithLabel("not a unicode character").
+			WithNote("surrogates and values above 10FFFF are not characters")
+		return utf8.RuneError, size
+	}
+	return rune(value), size
+}
+
+// isASCII checks if text holds only ASCII characters
+func isASCII(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if text[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// spanAt builds a diagnostic span inside a token
+// offset is a byte offset into the token's lexeme
+func (l *Lexer) spanAt(token Token, offset, length int) diagnostics.Span {
+	span := diagnostics.Span{File: token.file, Row: token.row, Col: token.col, Length: length}
+	before := token.lexeme[:min(offset, len(token.lexeme))]
+	if line := strings.LastIndexByte(before, '\n'); line != -1 {
+		span.Row += strings.Count(before, "\n")
+		span.Col = 1
+		before = before[line+1:]
+	}
+	span.Col += l.width(before)
+	return span
+}
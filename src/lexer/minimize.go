@@ -0,0 +1,188 @@
+package lexer
+
+import "fmt"
+
+// Minimize returns the smallest DFA that accepts the same tokens
+// uses Hopcroft's partition refinement algorithm
+//
+// states start out grouped by what they accept, non accepting states in one group
+// and accepting states in one group per token type. groups are then split until
+// every state in a group moves into the same group on every byte class.
+// missing transitions go to an implicit dead state, any group that ends up
+// with the dead state can never accept and is dropped from the result
+func (dfa *DFA) Minimize() *DFA {
+	classes := dfa.classes
+
+	// index states, the dead state takes the last index
+	index := make(map[*DFAState]int, len(dfa.states))
+	for i, state := range dfa.states {
+		index[state] = i
+	}
+	dead := len(dfa.states)
+	count := dead + 1
+
+	// inverse transitions, for each class the states that move into a state
+	// every byte of a class moves the same way, so its first byte stands for it
+	inverse := make([][][]int, len(classes))
+	for class, r := range classes {
+		sources := make([][]int, count)
+		for i, state := range dfa.states {
+			target := dead
+			if next := state.next(r.lo); next != nil {
+				target = index[next]
+			}
+			sources[target] = append(sources[target], i)
+		}
+		sources[dead] = append(sources[dead], dead)
+		inverse[class] = sources
+	}
+
+	// initial partition by accepted token type
+	var blocks [][]int
+	blockOf := make([]int, count)
+	groups := make(map[string]int)
+	for i := 0; i < count; i++ {
+		key := "reject"
+		if i != dead && dfa.states[i].isAccepting {
+			key = fmt.Sprintf("accept %d", dfa.states[i].tokenType)
+		}
+		block, exists := groups[key]
+		if !exists {
+			block = len(blocks)
+			groups[key] = block
+			blocks = append(blocks, nil)
+		}
+		blocks[block] = append(blocks[block], i)
+		blockOf[i] = block
+	}
+
+	// every initial block is a splitter
+	worklist := make([]int, 0, len(blocks))
+	inWorklist := make(map[int]bool)
+	for block := range blocks {
+		worklist = append(worklist, block)
+		inWorklist[block] = true
+	}
+
+	for len(worklist) > 0 {
+		splitter := worklist[len(worklist)-1]
+		worklist = worklist[:len(worklist)-1]
+		inWorklist[splitter] = false
+
+		// copy the splitter since splitting may change its block
+		members := append([]int(nil), blocks[splitter]...)
+
+		for class := range classes {
+			// the states that move into the splitter on this class
+			marked := make(map[int]bool)
+			touched := []int{}
+			for _, target := range members {
+				for _, source := range inverse[class][target] {
+					if marked[source] {
+						continue
+					}
+					marked[source] = true
+					if block := blockOf[source]; !contains(touched, block) {
+						touched = append(touched, block)
+					}
+				}
+			}
+
+			// split every block that is only partly marked
+			for _, block := range touched {
+				var in, out []int
+				for _, state := range blocks[block] {
+					if marked[state] {
+						in = append(in, state)
+					} else {
+						out = append(out, state)
+					}
+				}
+				if len(out) == 0 {
+					continue
+				}
+
+				blocks[block] = in
+				newBlock := len(blocks)
+				blocks = append(blocks, out)
+				for _, state := range out {
+					blockOf[state] = newBlock
+				}
+
+				// a block already waiting needs both halves checked,
+				// otherwise checking the smaller half is enough
+				if inWorklist[block] || len(out) <= len(in) {
+					worklist = append(worklist, newBlock)
+					inWorklist[newBlock] = true
+				} else {
+					worklist = append(worklist, block)
+					inWorklist[block] = true
+				}
+			}
+		}
+	}
+
+	return dfa.buildFromPartition(blocks, blockOf, index, dead)
+}
+
+// buildFromPartition creates one state per block reachable from the start
+// states are numbered in breadth first order so the result is the same on every run
+func (dfa *DFA) buildFromPartition(blocks [][]int, blockOf []int, index map[*DFAState]int, dead int) *DFA {
+	deadBlock := blockOf[dead]
+	states := make(map[int]*DFAState)
+	minimized := &DFA{classes: dfa.classes}
+
+	getState := func(block int) (*DFAState, bool) {
+		if state, exists := states[block]; exists {
+			return state, false
+		}
+		representative := dfa.states[blocks[block][0]]
+		state := &DFAState{
+			id:          len(minimized.states) + 1,
+			isAccepting: representative.isAccepting,
+			tokenType:   representative.tokenType,
+			priority:    representative.priority,
+		}
+		states[block] = state
+		minimized.states = append(minimized.states, state)
+		return state, true
+	}
+
+	startBlock := blockOf[index[dfa.start]]
+	minimized.start, _ = getState(startBlock)
+	queue := []int{startBlock}
+	for len(queue) > 0 {
+		block := queue[0]
+		queue = queue[1:]
+		state := states[block]
+		representative := dfa.states[blocks[block][0]]
+
+		for _, r := range dfa.classes {
+			next := representative.next(r.lo)
+			if next == nil {
+				continue
+			}
+			nextBlock := blockOf[index[next]]
+			if nextBlock == deadBlock {
+				continue
+			}
+			target, created := getState(nextBlock)
+			if created {
+				queue = append(queue, nextBlock)
+			}
+			state.addTransition(r, target)
+		}
+	}
+
+	return minimized
+}
+
+// contains checks if a block is in a list of blocks
+func contains(blocks []int, block int) bool {
+	for _, b := range blocks {
+		if b == block {
+			return true
+		}
+	}
+	return false
+}
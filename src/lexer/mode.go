@@ -0,0 +1,225 @@
+package lexer
+
+import "github.com/CFdefense/compiler/src/diagnostics"
+
+// What kind of source the lexer is reading
+// every open brace, string or interpolation pushes a mode which its closing
+// token pops, so the lexer always knows which context it is in without
+// looking back at the source
+type LexerMode int
+
+const (
+	MODE_NORMAL        LexerMode = iota // Sea source
+	MODE_ASM                            // AT&T assembly inside of an asm { } block
+	MODE_STRING                         // text of an interpolated string, between its quotes
+	MODE_INTERPOLATION                  // expression between the { } of an interpolated string
+)
+
+func (m LexerMode) String() string {
+	switch m {
+	case MODE_ASM:
+		return "asm"
+	case MODE_STRING:
+		return "string"
+	case MODE_INTERPOLATION:
+		return "interpolation"
+	default:
+		return "normal"
+	}
+}
+
+// modeFrame is one open context on the mode stack
+// open is the token that pushed it, used to point at a string that is never closed
+type modeFrame struct {
+	mode LexerMode
+	open Token
+}
+
+// ModeRegexDefs lists the token definitions of a mode in priority order
+// they are tried before the scanner, which lexes everything the mode's own
+// definitions do not match with TokenRegexDefs. string text is the exception,
+// it is lexed by its own definitions alone
+func ModeRegexDefs(mode LexerMode) []TokenRegexDef {
+	switch mode {
+	case MODE_ASM:
+		return []TokenRegexDef{
+			{"ASM_LABEL", ASM_LABEL_STR, T_ASM_LABEL},
+			{"ASM_MEMORY_REF", ASM_MEMORY_REF_STR, T_ASM_MEMORY_REF},
+			{"ASM_REGISTER", ASM_REGISTER_STR, T_ASM_REGISTER},
+			{"ASM_IMMEDIATE", ASM_IMMEDIATE_STR, T_ASM_IMMEDIATE},
+			{"ASM_INSTRUCTION", ASM_INSTRUCTION_STR, T_ASM_INSTRUCTION},
+		}
+	case MODE_STRING:
+		return []TokenRegexDef{
+			{"STRING_END", `^"`, T_STRING_END},
+			{"INTERPOLATION_START", `^\{`, T_INTERPOLATION_START},
+			{"STRING_FRAGMENT", STRING_FRAGMENT_PATTERN_STR, T_STRING_FRAGMENT},
+		}
+	case MODE_INTERPOLATION:
+		return []TokenRegexDef{
+			{"INTERPOLATION_END", `^\}`, T_INTERPOLATION_END},
+			{"STRING_START", `^"`, T_STRING_START},
+		}
+	default:
+		return []TokenRegexDef{
+			{"STRING_START", `^"`, T_STRING_START},
+		}
+	}
+}
+
+// every lexer mode, each has a scanner of its own built from its ModeRegexDefs
+var lexerModes = []LexerMode{MODE_NORMAL, MODE_ASM, MODE_STRING, MODE_INTERPOLATION}
+
+// handleMode lexes a token with the scanner of the current mode
+// the longest match that is allowed where it is wins, ties go to the earliest definition
+func (l *Lexer) handleMode(content string, pos int) (bool, int) {
+	length, matchType := GeneratedModeTables(l.mode()).MatchFunc(content, pos, func(tokenType TokenType) bool {
+		_, ok := l.modeTokenType(tokenType, content, pos)
+		return ok
+	})
+	if length == 0 {
+		return false, pos
+	}
+
+	tokenType, _ := l.modeTokenType(matchType, content, pos)
+	text := content[pos : pos+length]
+	token := createToken(tokenType, text, l.row, l.col)
+	l.addToken(token, pos)
+	l.checkUTF8(text, pos)
+	l.col += l.width(text)
+	return true, pos + length
+}
+
+// modeTokenType decides what a mode definition's match is where it was found
+// returns false when the definition does not apply there
+func (l *Lexer) modeTokenType(tokenType TokenType, content string, pos int) (TokenType, bool) {
+	switch tokenType {
+	case T_ASM_MEMORY_REF:
+		// the first word of a line is the instruction even when a ( follows it
+		return tokenType, !l.atAsmInstruction()
+	case T_ASM_INSTRUCTION:
+		if !l.atAsmInstruction() {
+			return T_IDENTIFIER, true
+		}
+	case T_STRING_START:
+		// a string without a { is an ordinary string literal
+		return tokenType, l.string_interpolation && interpolates(content, pos)
+	}
+	return tokenType, true
+}
+
+// interpolates checks if the string opened by the quote at pos holds an
+// unescaped { before its closing quote or the end of its line
+func interpolates(content string, pos int) bool {
+	for i := pos + 1; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			return true
+		case '"', '\n', '\r':
+			return false
+		case '\\':
+			i++
+		}
+	}
+	return false
+}
+
+// mode returns the mode of the innermost open context, normal outside of any
+func (l *Lexer) mode() LexerMode {
+	if len(l.modes) == 0 {
+		return MODE_NORMAL
+	}
+	return l.modes[len(l.modes)-1].mode
+}
+
+// inString checks if the lexer is inside of an interpolated string or one of its interpolations
+func (l *Lexer) inString() bool {
+	for _, frame := range l.modes {
+		if frame.mode == MODE_STRING {
+			return true
+		}
+	}
+	return false
+}
+
+// trackMode updates the mode stack after a token is added
+// a { right after the asm keyword opens an asm block, any other { a normal one
+// a ( only opens a context for the operands of extended asm
+// comments are skipped so they can sit anywhere without changing the context
+func (l *Lexer) trackMode(token Token) {
+	switch token.token_type {
+	case T_SINGLE_LINE_COMMENT, T_MULTI_LINE_COMMENT, T_SINGLE_LINE_DOC_COMMENT, T_MULTI_LINE_DOC_COMMENT:
+		return
+	case T_OPENING_BRACE:
+		mode := MODE_NORMAL
+		if l.previous.token_type == T_ASM {
+			mode = MODE_ASM
+		}
+		l.modes = append(l.modes, modeFrame{mode, token})
+	case T_STRING_START:
+		l.modes = append(l.modes, modeFrame{MODE_STRING, token})
+	case T_INTERPOLATION_START:
+		l.modes = append(l.modes, modeFrame{MODE_INTERPOLATION, token})
+	case T_OPENING_PAREN:
+		// a ( right after a string in an asm block opens the Sea expression an
+		// extended asm operand binds, lexed as Sea up to the ) that closes it
+		if l.inBinding() || (l.mode() == MODE_ASM && isStringLiteral(l.previous)) {
+			l.modes = append(l.modes, modeFrame{MODE_NORMAL, token})
+		}
+	case T_CLOSING_PAREN:
+		if l.inBinding() {
+			l.modes = l.modes[:len(l.modes)-1]
+		}
+	case T_CLOSING_BRACE:
+		// a binding left open is closed along with the block around it
+		// and a stray } is left for the parser to report
+		for l.inBinding() {
+			l.modes = l.modes[:len(l.modes)-1]
+		}
+		if len(l.modes) > 0 {
+			l.modes = l.modes[:len(l.modes)-1]
+		}
+	case T_STRING_END, T_INTERPOLATION_END:
+		if len(l.modes) > 0 {
+			l.modes = l.modes[:len(l.modes)-1]
+		}
+	}
+	l.previous = token
+}
+
+// inBinding checks if the innermost open context is the expression of an asm operand
+// parentheses inside of that expression push a context of their own
+func (l *Lexer) inBinding() bool {
+	return len(l.modes) > 0 && l.modes[len(l.modes)-1].open.token_type == T_OPENING_PAREN
+}
+
+// isStringLiteral checks if a token is a string an asm block can hold
+func isStringLiteral(token Token) bool {
+	return token.token_type == T_STRING_LITERAL || token.token_type == T_RAW_STRING_LITERAL
+}
+
+// handleUnterminatedString reports an interpolated string still open at the end of its line
+// the outermost open string is reported and every context inside of it is closed
+func (l *Lexer) handleUnterminatedString() {
+	for i, frame := range l.modes {
+		if frame.mode != MODE_STRING {
+			continue
+		}
+		l.diagnostics.Error(diagnostics.E_UNTERMINATED_LITERAL, l.spanAt(frame.open, 0, 1),
+			"unterminated string literal").
+			WithLabel("string starts here").
+			WithNote("no closing `\"` before the end of the line")
+		l.modes = l.modes[:i]
+		return
+	}
+}
+
+// atLineStart checks if no token other than a comment is on the current line yet
+func (l *Lexer) atLineStart() bool {
+	return l.previous.end_row < l.row
+}
+
+// Function to get the mode of the innermost open context
+func (l *Lexer) GetMode() LexerMode {
+	return l.mode()
+}
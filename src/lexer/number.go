@@ -0,0 +1,198 @@
+package lexer
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/CFdefense/compiler/src/diagnostics"
+)
+
+// type suffixes a number literal may end with, longest first so i128 is not read as i8
+var numberSuffixes = []string{
+	"i128", "u128",
+	"i16", "i32", "i64", "u16", "u32", "u64", "f32", "f64",
+	"i8", "u8",
+}
+
+// base of each number prefix, a number without one is decimal
+var numberRadixes = map[string]int{"0x": 16, "0o": 8, "0b": 2, "": 10}
+
+// splitNumber splits a number literal into its base prefix, digits and type suffix
+// a hex number never has a float suffix since f is one of its digits
+func splitNumber(text string) (prefix, digits, suffix string) {
+	if len(text) >= 2 && text[0] == '0' && strings.IndexByte("xob", text[1]) != -1 {
+		prefix = text[:2]
+	}
+	digits = text[len(prefix):]
+	for _, candidate := range numberSuffixes {
+		if prefix == "0x" && candidate[0] == 'f' {
+			continue
+		}
+		if len(digits) > len(candidate) && strings.HasSuffix(digits, candidate) {
+			suffix = candidate
+			digits = digits[:len(digits)-len(suffix)]
+			break
+		}
+	}
+	return prefix, digits, suffix
+}
+
+// numberTokenType tells float literals apart from integer literals
+// a float has a fraction, an exponent or a float suffix
+func numberTokenType(text string) TokenType {
+	prefix, digits, suffix := splitNumber(text)
+	switch {
+	case strings.HasPrefix(suffix, "f"),
+		prefix == "0x" && strings.ContainsAny(digits, ".pP"),
+		prefix == "" && strings.ContainsAny(digits, ".eE"):
+		return T_FLOAT_LITERAL
+	}
+	return T_INT_LITERAL
+}
+
+// decodeNumber gives an integer or float literal its value
+// a malformed number becomes T_ERROR without a value, one out of range for
+// its suffix becomes T_ERROR but keeps its value
+func (l *Lexer) decodeNumber(token *Token) {
+	prefix, digits, suffix := splitNumber(token.lexeme)
+	if !l.checkDigitSeparators(*token, prefix, digits) {
+		token.token_type = T_ERROR
+		return
+	}
+	radix := numberRadixes[prefix]
+	clean := strings.ReplaceAll(digits, "_", "")
+	if clean == "" {
+		token.token_type = T_ERROR
+		l.diagnostics.Error(diagnostics.E_INVALID_NUMBER, l.spanAt(*token, 0, l.width(token.lexeme)),
+			"missing digits after `%s`", prefix).
+			WithLabel("no digits follow the base prefix")
+		return
+	}
+
+	if token.token_type == T_INT_LITERAL {
+		integer, ok := new(big.Int).SetString(clean, radix)
+		if !ok || clean[0] == '+' || clean[0] == '-' {
+			return
+		}
+		token.value = &LiteralValue{kind: LITERAL_INT, integer: integer, radix: radix, suffix: suffix}
+
+		// an integer without a suffix is exact, the type it is used as decides if it fits
+		if suffix == "" {
+			return
+		}
+		bits, _ := strconv.Atoi(suffix[1:])
+		if integer.BitLen() > bits {
+			token.token_type = T_ERROR
+			l.diagnostics.Error(diagnostics.E_INTEGER_TOO_LARGE, l.spanAt(*token, 0, l.width(token.lexeme)),
+				"integer literal is too large%s", forSuffix(suffix)).
+				WithLabel(fmt.Sprintf("does not fit in %d bits", bits))
+		}
+		return
+	}
+
+	if suffix != "" && suffix[0] != 'f' {
+		token.token_type = T_ERROR
+		l.diagnostics.Error(diagnostics.E_INVALID_NUMBER, l.spanAt(*token, len(token.lexeme)-len(suffix), len(suffix)),
+			"float literal cannot have the integer suffix `%s`", suffix).
+			WithLabel("integer suffix").
+			WithNote("use `f32` or `f64`, or write the number without a fraction or exponent")
+		return
+	}
+	if radix == 2 || radix == 8 {
+		token.token_type = T_ERROR
+		l.diagnostics.Error(diagnostics.E_INVALID_NUMBER, l.spanAt(*token, 0, l.width(token.lexeme)),
+			"float literal cannot be written in base %d", radix).
+			WithLabel("only decimal and hexadecimal numbers can be floats")
+		return
+	}
+	if prefix == "0x" && !strings.ContainsAny(clean, "pP") {
+		token.token_type = T_ERROR
+		l.diagnostics.Error(diagnostics.E_INVALID_NUMBER, l.spanAt(*token, 0, l.width(token.lexeme)),
+			"hexadecimal float literal needs a `p` exponent").
+			WithLabel("missing exponent").
+			WithNote("write the power of two the mantissa is scaled by, as in `0x1.8p3`")
+		return
+	}
+	markers := "eE"
+	if prefix == "0x" {
+		markers = "pP"
+	}
+	if exponent := strings.IndexAny(digits, markers); exponent != -1 && strings.Trim(digits[exponent+1:], "+-") == "" {
+		token.token_type = T_ERROR
+		l.diagnostics.Error(diagnostics.E_INVALID_NUMBER, l.spanAt(*token, len(prefix)+exponent, len(digits)-exponent),
+			"missing exponent digits in float literal").
+			WithLabel(fmt.Sprintf("expected digits after `%c`", digits[exponent]))
+		return
+	}
+	// the exact value of 1e999999999 would not fit in memory
+	if exponent := strings.IndexAny(clean, markers); exponent != -1 && len(strings.TrimLeft(clean[exponent+1:], "+-0")) > 4 {
+		token.token_type = T_ERROR
+		l.diagnostics.Error(diagnostics.E_FLOAT_TOO_LARGE, l.spanAt(*token, 0, l.width(token.lexeme)),
+			"float literal exponent is out of range").
+			WithLabel("exponent has more than 4 digits")
+		return
+	}
+	float, ok := new(big.Rat).SetString(prefix + clean)
+	if !ok {
+		return
+	}
+	token.value = &LiteralValue{kind: LITERAL_FLOAT, float: float, radix: radix, suffix: suffix}
+
+	// a float is too large when it rounds to infinity in its type
+	bits, rounded := 64, 0.0
+	if suffix == "f32" {
+		f, _ := float.Float32()
+		bits, rounded = 32, float64(f)
+	} else {
+		rounded, _ = float.Float64()
+	}
+	if math.IsInf(rounded, 0) {
+		token.token_type = T_ERROR
+		l.diagnostics.Error(diagnostics.E_FLOAT_TOO_LARGE, l.spanAt(*token, 0, l.width(token.lexeme)),
+			"float literal is too large%s", forSuffix(suffix)).
+			WithLabel(fmt.Sprintf("rounds to infinity as a %d bit float", bits))
+	}
+}
+
+// forSuffix names the type suffix of a number in a message, empty if there is none
+func forSuffix(suffix string) string {
+	if suffix == "" {
+		return ""
+	}
+	return fmt.Sprintf(" for `%s`", suffix)
+}
+
+// checkDigitSeparators reports the first _ in a number that does not separate two digits
+// one may also follow a base prefix, as in 0x_ff
+func (l *Lexer) checkDigitSeparators(token Token, prefix, digits string) bool {
+	isDigit := func(c byte) bool {
+		if prefix == "0x" {
+			return isHexDigit(c)
+		}
+		return c >= '0' && c <= '9'
+	}
+
+	for i := 0; i < len(digits); i++ {
+		if digits[i] != '_' {
+			continue
+		}
+		before := (i == 0 && prefix != "") || (i > 0 && isDigit(digits[i-1]))
+		after := i+1 < len(digits) && isDigit(digits[i+1])
+		if before && after {
+			continue
+		}
+		l.diagnostics.Error(diagnostics.E_INVALID_NUMBER, l.spanAt(token, len(prefix)+i, 1),
+			"invalid digit separator in number literal").
+			WithLabel("`_` must separate digits")
+		return false
+	}
+	return true
+}
+
+// isHexDigit checks if a character is a hexadecimal digit
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,97 @@
+package lexer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// byteRange is every byte from lo to hi, both included
+type byteRange struct {
+	lo, hi byte
+}
+
+// contains checks if a byte is in the range
+func (r byteRange) contains(b byte) bool {
+	return r.lo <= b && b <= r.hi
+}
+
+// String writes the range readably, like a-z or \n
+func (r byteRange) String() string {
+	if r.lo == r.hi {
+		return formatByte(r.lo)
+	}
+	return formatByte(r.lo) + "-" + formatByte(r.hi)
+}
+
+// byteClasses splits the bytes an NFA moves on into equivalence classes
+// a class is a range of bytes no transition of the NFA tells apart, so every
+// byte in it moves every set of NFA states to the same set, and automata built
+// from the NFA only need to look at one byte per class
+// bytes that no transition uses belong to no class
+func byteClasses(nfa *NFA) []byteRange {
+	var boundary [257]bool
+	var used [256]bool
+	for _, state := range nfa.reachableStates() {
+		for _, t := range state.transitions {
+			boundary[t.lo] = true
+			boundary[int(t.hi)+1] = true
+			for b := int(t.lo); b <= int(t.hi); b++ {
+				used[b] = true
+			}
+		}
+	}
+
+	var classes []byteRange
+	for b := 0; b < 256; b++ {
+		if !used[b] {
+			continue
+		}
+		if boundary[b] || len(classes) == 0 || int(classes[len(classes)-1].hi) != b-1 {
+			classes = append(classes, byteRange{lo: byte(b), hi: byte(b)})
+		} else {
+			classes[len(classes)-1].hi = byte(b)
+		}
+	}
+	return classes
+}
+
+// formatByteRanges writes a set of ranges sorted and merged, like 0-9 A-Z _ a-z
+func formatByteRanges(ranges []byteRange) string {
+	sorted := slices.Clone(ranges)
+	slices.SortFunc(sorted, func(a, b byteRange) int { return int(a.lo) - int(b.lo) })
+
+	var merged []byteRange
+	for _, r := range sorted {
+		if last := len(merged) - 1; last >= 0 && int(r.lo) <= int(merged[last].hi)+1 {
+			merged[last].hi = max(merged[last].hi, r.hi)
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	parts := make([]string, len(merged))
+	for i, r := range merged {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatByte writes a byte readably, escaping anything that is not visible
+func formatByte(b byte) string {
+	switch {
+	case b == '\n':
+		return `\n`
+	case b == '\t':
+		return `\t`
+	case b == '\r':
+		return `\r`
+	case b == ' ':
+		return `' '`
+	case b == '-':
+		return `'-'`
+	case b > ' ' && b < 0x7f:
+		return string(b)
+	}
+	return fmt.Sprintf(`\x%02x`, b)
+}
@@ -0,0 +1,181 @@
+package lexer
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+
+	"github.com/CFdefense/compiler/src/diagnostics"
+)
+
+// bytes read from the source at a time, also how much lexed source
+// is allowed to build up in the buffer before it is dropped
+const streamChunkSize = 32 * 1024
+
+// TokenStream lexes a source file incrementally from an io.Reader
+// tokens are produced on demand so only a window of the source and the
+// tokens not yet consumed are held in memory
+//
+// the same handlers as the batch lexer are used, so the tokens match
+// LexicalAnalysis on the same source exactly. source snippets are not
+// registered with the reporter since the whole file is never in memory
+type TokenStream struct {
+	lexer  *Lexer // lexes the buffer, its token stream holds the tokens not yet consumed
+	reader io.Reader
+	chunk  []byte
+	buffer string // window of the source, starts at lexer.offset in the file
+	pos    int    // position of the next token in the buffer
+	next   int    // index of the next token in the lexer token stream
+	eof    bool
+	done   bool // the whole source has been lexed
+	err    error
+}
+
+// Stream starts lexing a file from a reader
+// the stream shares this lexer's scanner, reporter and options but not its tokens
+func (l *Lexer) Stream(file string, reader io.Reader) *TokenStream {
+	l.debug.DebugLog(fmt.Sprintf("Streaming file: %s", file), false)
+	return &TokenStream{
+		lexer: &Lexer{
+			token_stream:         []Token{},
+			file_streams:         make(map[string][]Token),
+			end_trivia:           make(map[string]string),
+			content:              make(map[string]string),
+			file:                 file,
+			row:                  1,
+			col:                  1,
+			columns:              l.columns,
+			unicode_ids:          l.unicode_ids,
+			legacy_comments:      l.legacy_comments,
+			string_interpolation: l.string_interpolation,
+			debug:                l.debug,
+			diagnostics:          l.diagnostics,
+			scanner:              l.scanner,
+		},
+		reader: reader,
+		chunk:  make([]byte, streamChunkSize),
+	}
+}
+
+// NextToken consumes and returns the next token
+// false is returned once the source is exhausted
+func (s *TokenStream) NextToken() (Token, bool) {
+	token, ok := s.Peek(0)
+	if ok {
+		s.next++
+	}
+	return token, ok
+}
+
+// Peek returns the token n tokens ahead without consuming anything
+// Peek(0) is the token NextToken will return
+// a token is only handed out once the token after it is lexed, until then
+// the whitespace after it may still be added to its trailing trivia
+func (s *TokenStream) Peek(n int) (Token, bool) {
+	for !s.done && s.next+n+1 >= len(s.lexer.token_stream) {
+		s.done = !s.advance()
+	}
+	if s.next+n >= len(s.lexer.token_stream) {
+		return Token{}, false
+	}
+	return s.lexer.token_stream[s.next+n], true
+}
+
+// All returns an iterator that consumes the rest of the stream
+func (s *TokenStream) All() iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		for token, ok := s.NextToken(); ok; token, ok = s.NextToken() {
+			if !yield(token) {
+				return
+			}
+		}
+	}
+}
+
+// Function to get the error that stopped reading the source, if any
+// the end of the source is not an error
+func (s *TokenStream) Err() error {
+	return s.err
+}
+
+// advance lexes the next token(s) from the source
+// returns false when the whole source has been lexed
+func (s *TokenStream) advance() bool {
+	s.compact()
+	for !s.eof && s.needsInput() {
+		s.fill()
+	}
+	if s.pos >= len(s.buffer) {
+		if s.lexer.inString() {
+			s.lexer.handleUnterminatedString()
+		}
+		s.lexer.finishTrivia()
+		return false
+	}
+	s.pos = s.lexer.step(s.buffer, s.pos)
+	s.trim()
+	return true
+}
+
+// needsInput checks if the buffer might end in the middle of the next token
+// every token ends by the end of its line except comments and literals,
+// which are read up to their closing delimiter
+func (s *TokenStream) needsInput() bool {
+	rest := s.buffer[s.pos:]
+	prefix, closing := literalPrefix(rest, 0)
+	switch {
+	case strings.HasPrefix(rest, "/*"):
+		end, _ := commentEnd(rest, 0)
+		return end == -1
+	case strings.HasPrefix(rest, "\"") || strings.HasPrefix(rest, "'"):
+		return closingQuote(rest, 0) == -1
+	case prefix > 0 && rest[0] == 'r':
+		return !strings.Contains(rest[prefix:], closing)
+	case prefix > 0:
+		return closingQuote(rest, 1) == -1
+	}
+	return !strings.Contains(rest, "\n")
+}
+
+// fill reads the next chunk of the source into the buffer
+func (s *TokenStream) fill() {
+	n, err := s.reader.Read(s.chunk)
+	s.buffer += string(s.chunk[:n])
+	if err == nil {
+		return
+	}
+
+	s.eof = true
+	if err != io.EOF {
+		s.err = err
+		s.lexer.diagnostics.Error(diagnostics.E_UNREADABLE_SOURCE, s.lexer.span(0),
+			"failed to read source file: %v", err)
+	}
+}
+
+// compact drops consumed tokens once they make up half of the token stream
+func (s *TokenStream) compact() {
+	if s.next == 0 || s.next*2 < len(s.lexer.token_stream) {
+		return
+	}
+	remaining := copy(s.lexer.token_stream, s.lexer.token_stream[s.next:])
+	s.lexer.token_stream = s.lexer.token_stream[:remaining]
+	s.next = 0
+}
+
+// trim drops lexed source from the front of the buffer
+// the handlers look at the character before pos, so it is kept, anything further
+// back they need is in the lexer's mode stack and previous token instead
+// source is only dropped a chunk at a time so the copying stays cheap
+func (s *TokenStream) trim() {
+	keep := s.pos - 1
+	if keep < streamChunkSize {
+		return
+	}
+
+	// cloned so the dropped source can be freed
+	s.buffer = strings.Clone(s.buffer[keep:])
+	s.pos -= keep
+	s.lexer.offset += keep
+}
@@ -0,0 +1,244 @@
+package lexer
+
+import (
+	"fmt"
+	"go/format"
+	"slices"
+	"strings"
+)
+
+//go:generate go run ./gen -o scanner_tables.go
+
+// Scanner finds the longest token starting at a position
+// returns the length of the match and its token type, a length of 0 means no match
+type Scanner interface {
+	Match(input string, pos int) (int, TokenType)
+}
+
+// ScannerTables is the scanner DFA flattened into arrays
+// bytes are first mapped to an equivalence class, every byte in a class
+// moves every state to the same next state
+// Transitions holds one row of ClassCount entries per state, -1 means no transition
+// Accepts holds the token type each state accepts, -1 means the state does not accept
+// state 0 is the start state
+type ScannerTables struct {
+	ClassCount  int
+	Classes     [256]uint8
+	Transitions []int16
+	Accepts     []int16
+}
+
+// generated once from the tables in scanner_tables.go
+var generatedScanner = &ScannerTables{
+	ClassCount:  scannerClassCount,
+	Classes:     scannerClasses,
+	Transitions: scannerTransitions,
+	Accepts:     scannerAccepts,
+}
+
+// the tables of every mode scanner
+var generatedModeScanners = map[LexerMode]*ScannerTables{
+	MODE_NORMAL: {
+		ClassCount:  normalScannerClassCount,
+		Classes:     normalScannerClasses,
+		Transitions: normalScannerTransitions,
+		Accepts:     normalScannerAccepts,
+	},
+	MODE_ASM: {
+		ClassCount:  asmScannerClassCount,
+		Classes:     asmScannerClasses,
+		Transitions: asmScannerTransitions,
+		Accepts:     asmScannerAccepts,
+	},
+	MODE_STRING: {
+		ClassCount:  stringScannerClassCount,
+		Classes:     stringScannerClasses,
+		Transitions: stringScannerTransitions,
+		Accepts:     stringScannerAccepts,
+	},
+	MODE_INTERPOLATION: {
+		ClassCount:  interpolationScannerClassCount,
+		Classes:     interpolationScannerClasses,
+		Transitions: interpolationScannerTransitions,
+		Accepts:     interpolationScannerAccepts,
+	},
+}
+
+// GeneratedScannerTables returns the scanner tables built by go generate
+// the tables are never modified so every lexer can share them
+func GeneratedScannerTables() *ScannerTables {
+	return generatedScanner
+}
+
+// GeneratedModeTables returns the tables of a mode's scanner built by go generate
+func GeneratedModeTables(mode LexerMode) *ScannerTables {
+	return generatedModeScanners[mode]
+}
+
+// Match walks the tables from the start state, see Scanner
+func (t *ScannerTables) Match(input string, pos int) (int, TokenType) {
+	return t.MatchFunc(input, pos, nil)
+}
+
+// MatchFunc is Match that only takes token types keep allows
+// a nil keep allows every token type
+func (t *ScannerTables) MatchFunc(input string, pos int, keep func(TokenType) bool) (int, TokenType) {
+	matchLength := 0
+	var matchType TokenType
+
+	state := 0
+	for i := pos; i < len(input); i++ {
+		next := t.Transitions[state*t.ClassCount+int(t.Classes[input[i]])]
+		if next < 0 {
+			break
+		}
+		state = int(next)
+		if accept := t.Accepts[state]; accept >= 0 && (keep == nil || keep(TokenType(accept))) {
+			matchLength = i + 1 - pos
+			matchType = TokenType(accept)
+		}
+	}
+
+	return matchLength, matchType
+}
+
+// BuildScannerTables flattens a DFA into tables
+// the DFA start state becomes state 0 and the rest keep their order
+func BuildScannerTables(dfa *DFA) *ScannerTables {
+	// number the states with the start state first
+	order := []*DFAState{dfa.start}
+	for _, state := range dfa.states {
+		if state != dfa.start {
+			order = append(order, state)
+		}
+	}
+	index := make(map[*DFAState]int, len(order))
+	for i, state := range order {
+		index[state] = i
+	}
+
+	// next state of every state for one byte, used to find equivalent bytes
+	column := func(b byte) []int16 {
+		targets := make([]int16, len(order))
+		for i, state := range order {
+			targets[i] = -1
+			if next := state.next(b); next != nil {
+				targets[i] = int16(index[next])
+			}
+		}
+		return targets
+	}
+
+	// bytes with the same column share a class, classes are numbered by their first byte
+	tables := &ScannerTables{}
+	var columns [][]int16
+	for b := 0; b < 256; b++ {
+		targets := column(byte(b))
+		class := slices.IndexFunc(columns, func(c []int16) bool { return slices.Equal(c, targets) })
+		if class == -1 {
+			class = len(columns)
+			columns = append(columns, targets)
+		}
+		tables.Classes[b] = uint8(class)
+	}
+	tables.ClassCount = len(columns)
+
+	tables.Transitions = make([]int16, len(order)*tables.ClassCount)
+	tables.Accepts = make([]int16, len(order))
+	for i, state := range order {
+		for class, targets := range columns {
+			tables.Transitions[i*tables.ClassCount+class] = targets[i]
+		}
+		tables.Accepts[i] = -1
+		if state.isAccepting {
+			tables.Accepts[i] = int16(state.tokenType)
+		}
+	}
+
+	return tables
+}
+
+// writeSource writes the declarations of the tables, every name starts with prefix
+func (t *ScannerTables) writeSource(sb *strings.Builder, name, prefix string) {
+	sb.WriteString(fmt.Sprintf("\n// %s DFA with %d states over %d byte classes\n", name, len(t.Accepts), t.ClassCount))
+	sb.WriteString(fmt.Sprintf("const %sClassCount = %d\n\n", prefix, t.ClassCount))
+
+	sb.WriteString("// byte class of every input byte\n")
+	sb.WriteString(fmt.Sprintf("var %sClasses = [256]uint8{\n", prefix))
+	for row := 0; row < 256; row += 16 {
+		for _, class := range t.Classes[row : row+16] {
+			sb.WriteString(fmt.Sprintf("%d, ", class))
+		}
+		sb.WriteString(fmt.Sprintf("// 0x%02x\n", row))
+	}
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// next state by state and byte class, -1 means no transition\n")
+	sb.WriteString(fmt.Sprintf("var %sTransitions = []int16{\n", prefix))
+	for state := range t.Accepts {
+		for _, next := range t.Transitions[state*t.ClassCount : (state+1)*t.ClassCount] {
+			sb.WriteString(fmt.Sprintf("%d, ", next))
+		}
+		sb.WriteString(fmt.Sprintf("// state %d\n", state))
+	}
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// token type accepted by each state, -1 means the state does not accept\n")
+	sb.WriteString(fmt.Sprintf("var %sAccepts = []int16{\n", prefix))
+	for state, accept := range t.Accepts {
+		if accept < 0 {
+			sb.WriteString(fmt.Sprintf("-1, // state %d\n", state))
+		} else {
+			sb.WriteString(fmt.Sprintf("int16(%s), // state %d\n", TokenType(accept).String(), state))
+		}
+	}
+	sb.WriteString("}\n")
+}
+
+// GenerateScannerTables builds the scanner automata from TokenRegexDefs and
+// ModeRegexDefs and returns the Go source of the matching scanner_tables.go
+func GenerateScannerTables() ([]byte, error) {
+	automata := CompiledAutomata()
+
+	var sb strings.Builder
+	sb.WriteString("// Code generated by go run ./gen; DO NOT EDIT.\n\n")
+	sb.WriteString("package lexer\n")
+	automata.GetTables().writeSource(&sb, "scanner", "scanner")
+	for _, mode := range lexerModes {
+		automata.GetMode(mode).GetTables().writeSource(&sb, mode.String()+" mode scanner", mode.String()+"Scanner")
+	}
+	return format.Source([]byte(sb.String()))
+}
+
+// VerifyScannerTables checks the generated tables against automata built at runtime
+// an error means the token definitions changed without running go generate
+func VerifyScannerTables() error {
+	automata := CompiledAutomata()
+	if err := compareTables("scanner", automata.GetTables(), GeneratedScannerTables()); err != nil {
+		return err
+	}
+	for _, mode := range lexerModes {
+		name := mode.String() + " mode scanner"
+		if err := compareTables(name, automata.GetMode(mode).GetTables(), GeneratedModeTables(mode)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareTables checks that generated tables are the tables built at runtime
+func compareTables(name string, built, generated *ScannerTables) error {
+	switch {
+	case built.ClassCount != generated.ClassCount:
+		return fmt.Errorf("%s tables are stale: %d byte classes generated, %d built", name, generated.ClassCount, built.ClassCount)
+	case len(built.Accepts) != len(generated.Accepts):
+		return fmt.Errorf("%s tables are stale: %d states generated, %d built", name, len(generated.Accepts), len(built.Accepts))
+	case built.Classes != generated.Classes:
+		return fmt.Errorf("%s tables are stale: byte classes differ", name)
+	case !slices.Equal(built.Transitions, generated.Transitions):
+		return fmt.Errorf("%s tables are stale: transitions differ", name)
+	case !slices.Equal(built.Accepts, generated.Accepts):
+		return fmt.Errorf("%s tables are stale: accepting states differ", name)
+	}
+	return nil
+}
@@ -2,22 +2,39 @@ package lexer
 
 import "regexp"
 
-// Original regex pattern strings (for postfix conversion)
+// Original regex pattern strings, the regex package compiles them into the automata
+// of TokenRegexDefs and ModeRegexDefs
 const (
 	// Comments first (highest priority)
-	SINGLE_LINE_COMMENT_PATTERN_STR = `^#[^\n]*`
-	MULTI_LINE_COMMENT_PATTERN_STR  = `^/\*[\s\S]*?\*/`
+	// block comments nest, which no regex can count, so handleMultiLineComment
+	// finds where they end and these patterns only match the unnested forms
+	SINGLE_LINE_COMMENT_PATTERN_STR     = `^#[^\n]*`
+	MULTI_LINE_COMMENT_PATTERN_STR      = `^/\*[\s\S]*?\*/`
+	SINGLE_LINE_DOC_COMMENT_PATTERN_STR = `^///[^\n]*`
+	MULTI_LINE_DOC_COMMENT_PATTERN_STR  = `^/\*\*[^*/][\s\S]*?\*/`
 
 	// String literals (must start with quote)
 	STRING_PATTERN_STR          = `^"([^"\\]|\\.)*"`
 	CHAR_PATTERN_STR            = `^'([^'\\]|\\.)'`
 	ESCAPE_SEQUENCE_PATTERN_STR = `^\\[ntr\\"']`
 
+	// Text of an interpolated string, up to its closing quote, next { or the end of the line
+	STRING_FRAGMENT_PATTERN_STR = `^([^"\\{\r\n]|\\.?)+`
+
+	// Raw and byte literals, a raw string has no escapes
+	// r#"..."# needs as many # to close as it opened with, which no regex can count,
+	// so only handlePrefixedLiteral lexes it
+	RAW_STRING_PATTERN_STR  = `^r"[^"]*"`
+	BYTE_STRING_PATTERN_STR = `^b"([^"\\]|\\.)*"`
+	BYTE_CHAR_PATTERN_STR   = `^b'([^'\\]|\\.)'`
+
 	// Boolean literals
 	BOOL_PATTERN_STR = `^(true|false)\b`
 
-	// Numbers
-	NUMBER_PATTERN_STR = `^(0x[0-9a-fA-F]+|0b[01]+|[0-9]+)`
+	// Numbers, integers and floats in any base with an optional type suffix
+	// _ may separate digits, where it is allowed is checked when the value is decoded
+	NUMBER_PATTERN_STR = `^(0x([0-9a-fA-F_]+(\.[0-9a-fA-F_]+)?([pP][+-]?[0-9_]*)?)?|0o[0-7_]*|0b[01_]*|` +
+		`[0-9][0-9_]*(\.[0-9][0-9_]*)?([eE][+-]?[0-9_]*)?)([iu](8|16|32|64|128)|f(32|64))?`
 
 	// Keywords (must be before identifiers)
 	KEYWORD_PATTERN_STR = `^(if|else|while|do|for|match|enum|struct|const|void|int|bool|mut|return|default|break|continue|sizeof|asm)`
@@ -37,12 +54,14 @@ const (
 	// Special tokens
 	SPECIAL_PATTERN_STR = `^(@|&mut\b|&|\$|~|` + "`" + `)`
 
-	// ASM patterns (lowest priority)
-	ASM_INSTRUCTION_STR = `^[a-z][a-z0-9]*\b`
-	ASM_REGISTER_STR    = `^%([re][a-z]{2}|[re]?[0-9]+|[re]?[ax|bx|cx|dx|si|di|sp|bp]|[re]?ip)\b`
-	ASM_IMMEDIATE_STR   = `^\$?(0x[0-9a-fA-F]+|[0-9]+|[a-zA-Z_][a-zA-Z0-9_]*)\b`
-	ASM_MEMORY_REF_STR  = `^\(\s*%[a-zA-Z0-9]+\s*\)`
-	ASM_LABEL_STR       = `^[a-zA-Z_][a-zA-Z0-9_]*:`
+	// ASM patterns, the definitions of the asm mode (AT&T syntax)
+	// a word is an instruction at the start of an asm line and a symbol after it
+	ASM_INSTRUCTION_STR = `^[a-zA-Z_.][a-zA-Z0-9_.]*`
+	ASM_REGISTER_STR    = `^%[a-zA-Z][a-zA-Z0-9]*(\([0-9]\))?`
+	ASM_IMMEDIATE_STR   = `^\$-?(0[xX][0-9a-fA-F]+|[0-9]+|[a-zA-Z_.][a-zA-Z0-9_.]*)`
+	ASM_MEMORY_REF_STR  = `^-?(0[xX][0-9a-fA-F]+|[0-9]+|[a-zA-Z_.][a-zA-Z0-9_.]*)?` +
+		`\([ \t]*(%[a-zA-Z][a-zA-Z0-9]*)?([ \t]*,[ \t]*%[a-zA-Z][a-zA-Z0-9]*([ \t]*,[ \t]*[0-9]+)?)?[ \t]*\)`
+	ASM_LABEL_STR = `^([a-zA-Z_.][a-zA-Z0-9_.]*|[0-9]+):`
 )
 
 // Regex patterns for token matching
@@ -77,12 +96,19 @@ var (
 	// Character literals
 	CHAR_PATTERN = regexp.MustCompile(CHAR_PATTERN_STR)
 
+	// Raw strings, byte strings and byte characters
+	RAW_STRING_PATTERN  = regexp.MustCompile(RAW_STRING_PATTERN_STR)
+	BYTE_STRING_PATTERN = regexp.MustCompile(BYTE_STRING_PATTERN_STR)
+	BYTE_CHAR_PATTERN   = regexp.MustCompile(BYTE_CHAR_PATTERN_STR)
+
 	// Escape sequences
 	ESCAPE_SEQUENCE_PATTERN = regexp.MustCompile(ESCAPE_SEQUENCE_PATTERN_STR)
 
 	// Comments
-	SINGLE_LINE_COMMENT_PATTERN = regexp.MustCompile(SINGLE_LINE_COMMENT_PATTERN_STR)
-	MULTI_LINE_COMMENT_PATTERN  = regexp.MustCompile(MULTI_LINE_COMMENT_PATTERN_STR)
+	SINGLE_LINE_COMMENT_PATTERN     = regexp.MustCompile(SINGLE_LINE_COMMENT_PATTERN_STR)
+	MULTI_LINE_COMMENT_PATTERN      = regexp.MustCompile(MULTI_LINE_COMMENT_PATTERN_STR)
+	SINGLE_LINE_DOC_COMMENT_PATTERN = regexp.MustCompile(SINGLE_LINE_DOC_COMMENT_PATTERN_STR)
+	MULTI_LINE_DOC_COMMENT_PATTERN  = regexp.MustCompile(MULTI_LINE_DOC_COMMENT_PATTERN_STR)
 
 	// ASM-specific patterns
 	ASM_BLOCK_START = regexp.MustCompile(`^asm\s*{`)
@@ -94,17 +120,22 @@ var (
 	// Register pattern (e.g., %rax, %rbx, %eax, %r8)
 	ASM_REGISTER = regexp.MustCompile(ASM_REGISTER_STR)
 
-	// Immediate value pattern (e.g., $123, $0xFF, label_name)
+	// Immediate value pattern (e.g., $123, $0xFF, $label_name)
 	ASM_IMMEDIATE = regexp.MustCompile(ASM_IMMEDIATE_STR)
 
-	// Memory reference pattern (e.g., (%rax), 8(%rbp), -16(%rbp))
+	// Memory reference pattern (e.g., (%rax), -16(%rbp), 8(%rsp, %rdi, 4), msg(%rip))
 	ASM_MEMORY_REF = regexp.MustCompile(ASM_MEMORY_REF_STR)
 
-	// Assembly label pattern (e.g., loop_start:, main:)
+	// Assembly label pattern (e.g., loop_start:, .L1:, 1:)
 	ASM_LABEL = regexp.MustCompile(ASM_LABEL_STR)
 
 	// Operand separator
 	ASM_SEPARATOR = regexp.MustCompile(`^,\s*`)
+
+	// Instruction prefixes, the word after one is an instruction as well
+	ASM_PREFIXES = map[string]bool{
+		"lock": true, "rep": true, "repe": true, "repz": true, "repne": true, "repnz": true,
+	}
 )
 
 // 'Enum' for token types
@@ -129,8 +160,8 @@ const (
 	T_STRING_LITERAL      // String literals with escape sequences
 	T_CHAR_LITERAL        // Character literals ('a', '\n')
 	T_ESCAPE_SEQUENCE     // Escape sequences in strings (\n, \t, \", etc.)
-	T_RAW_STRING_LITERAL  // Raw string literals (r"...") - for regex
-	T_BYTE_STRING_LITERAL // Byte string literals (b"...") - for regex
+	T_RAW_STRING_LITERAL  // Raw string literals (r"..." and r#"..."#)
+	T_BYTE_STRING_LITERAL // Byte string literals (b"...")
 
 	// Type-related
 	T_TYPE_QUALIFIER  // Type qualifiers (mut)
@@ -174,6 +205,7 @@ const (
 	T_OR             // Logical OR operator (||)
 	T_NOT            // Logical NOT operator (!)
 	T_XOR            // XOR operator (^)
+	T_PIPE           // Bitwise OR operator (|)
 	T_ASSIGN         // Assignment operator (=)
 	T_DECLARE_ASSIGN // Declaration assignment operator (:=)
 	T_AT             // At symbol (@)
@@ -234,40 +266,111 @@ const (
 	T_STRING_LITERAL_RAW // Raw string literal
 	T_CHAR_LITERAL_RAW   // Raw character literal
 	T_UNDERSCORE         // Underscore pattern for match arms
+	T_FLOAT_LITERAL      // Floating point literal
+	T_FLOAT_TYPE         // float type keyword
+
+	// Sized integer type keywords
+	T_I8_TYPE   // i8 type keyword
+	T_I16_TYPE  // i16 type keyword
+	T_I32_TYPE  // i32 type keyword
+	T_I64_TYPE  // i64 type keyword
+	T_I128_TYPE // i128 type keyword
+	T_U8_TYPE   // u8 type keyword
+	T_U16_TYPE  // u16 type keyword
+	T_U32_TYPE  // u32 type keyword
+	T_U64_TYPE  // u64 type keyword
+	T_U128_TYPE // u128 type keyword
+	T_BIG_TYPE  // big type keyword, an integer of any width
+
+	T_BYTE_CHAR_LITERAL // Byte character literals (b'x')
+
+	// Documentation comments, attached by the parser to the declaration after them
+	T_SINGLE_LINE_DOC_COMMENT // Single line doc comments (///)
+	T_MULTI_LINE_DOC_COMMENT  // Multi-line doc comments (/** */)
+
+	// Interpolated strings, "x = {x}" is lexed as start, fragment, interpolation and end
+	T_STRING_START        // Opening quote of an interpolated string
+	T_STRING_FRAGMENT     // Text of an interpolated string between its interpolations
+	T_STRING_END          // Closing quote of an interpolated string
+	T_INTERPOLATION_START // { that opens an interpolation
+	T_INTERPOLATION_END   // } that closes an interpolation
 )
 
 // TokenRegexDef represents a regex definition for a token
 type TokenRegexDef struct {
 	Name      string
 	Pattern   string
-	Postfix   string
 	TokenType TokenType
 }
 
 // Individual token object
+// start and end are byte offsets into the source file, end is exclusive
+// end_row and end_col are the position just past the last character
+// value is the decoded value of a literal token, nil for every other token
+// leading and trailing are the whitespace around the token, see trivia.go
 type Token struct {
 	token_type TokenType
 	lexeme     string
+	file       string
 	row        int
 	col        int
+	start      int
+	end        int
+	end_row    int
+	end_col    int
+	value      *LiteralValue
+	leading    string
+	trailing   string
 }
 
-func (t *Token) GetTokenContent() string {
+func (t Token) GetTokenContent() string {
 	return t.lexeme
 }
 
-func (t *Token) GetTokenType() TokenType {
+func (t Token) GetTokenType() TokenType {
 	return t.token_type
 }
 
-func (t *Token) GetRow() int {
+func (t Token) GetRow() int {
 	return t.row
 }
 
-func (t *Token) GetCol() int {
+func (t Token) GetCol() int {
 	return t.col
 }
 
+func (t Token) GetFile() string {
+	return t.file
+}
+
+func (t Token) GetStart() int {
+	return t.start
+}
+
+func (t Token) GetEnd() int {
+	return t.end
+}
+
+func (t Token) GetEndRow() int {
+	return t.end_row
+}
+
+func (t Token) GetEndCol() int {
+	return t.end_col
+}
+
+// Function to get the decoded value of a literal token, nil if it has none
+func (t Token) GetValue() *LiteralValue {
+	return t.value
+}
+
+// Equal checks if two tokens are the same, values are compared by what they hold
+func (t Token) Equal(other Token) bool {
+	value, otherValue := t.value, other.value
+	t.value, other.value = nil, nil
+	return t == other && value.Equal(otherValue)
+}
+
 // String converts TokenType to its string representation
 func (tt TokenType) String() string {
 	switch tt {
@@ -363,6 +466,8 @@ func (tt TokenType) String() string {
 		return "T_NOT"
 	case T_XOR:
 		return "T_XOR"
+	case T_PIPE:
+		return "T_PIPE"
 	case T_ASSIGN:
 		return "T_ASSIGN"
 	case T_DECLARE_ASSIGN:
@@ -463,6 +568,48 @@ func (tt TokenType) String() string {
 		return "T_CHAR_LITERAL_RAW"
 	case T_UNDERSCORE:
 		return "T_UNDERSCORE"
+	case T_FLOAT_LITERAL:
+		return "T_FLOAT_LITERAL"
+	case T_FLOAT_TYPE:
+		return "T_FLOAT_TYPE"
+	case T_I8_TYPE:
+		return "T_I8_TYPE"
+	case T_I16_TYPE:
+		return "T_I16_TYPE"
+	case T_I32_TYPE:
+		return "T_I32_TYPE"
+	case T_I64_TYPE:
+		return "T_I64_TYPE"
+	case T_I128_TYPE:
+		return "T_I128_TYPE"
+	case T_U8_TYPE:
+		return "T_U8_TYPE"
+	case T_U16_TYPE:
+		return "T_U16_TYPE"
+	case T_U32_TYPE:
+		return "T_U32_TYPE"
+	case T_U64_TYPE:
+		return "T_U64_TYPE"
+	case T_U128_TYPE:
+		return "T_U128_TYPE"
+	case T_BIG_TYPE:
+		return "T_BIG_TYPE"
+	case T_BYTE_CHAR_LITERAL:
+		return "T_BYTE_CHAR_LITERAL"
+	case T_SINGLE_LINE_DOC_COMMENT:
+		return "T_SINGLE_LINE_DOC_COMMENT"
+	case T_MULTI_LINE_DOC_COMMENT:
+		return "T_MULTI_LINE_DOC_COMMENT"
+	case T_STRING_START:
+		return "T_STRING_START"
+	case T_STRING_FRAGMENT:
+		return "T_STRING_FRAGMENT"
+	case T_STRING_END:
+		return "T_STRING_END"
+	case T_INTERPOLATION_START:
+		return "T_INTERPOLATION_START"
+	case T_INTERPOLATION_END:
+		return "T_INTERPOLATION_END"
 	default:
 		return "T_UNKNOWN"
 	}
@@ -0,0 +1,127 @@
+package lexer
+
+import "strings"
+
+// What a piece of trivia is
+type TriviaKind int
+
+const (
+	TRIVIA_WHITESPACE  TriviaKind = iota // spaces, tabs and a \r that ends no line
+	TRIVIA_NEWLINE                       // \n or \r\n
+	TRIVIA_COMMENT                       // # and /* */ comments
+	TRIVIA_DOC_COMMENT                   // /// and /** */ comments
+)
+
+// Trivia is source text that sits between tokens and means nothing to the parser
+// the lexer only keeps whitespace as trivia, comments stay tokens of their own
+// and become trivia when the parser builds the concrete syntax tree
+type Trivia struct {
+	kind TriviaKind
+	text string
+}
+
+func (t Trivia) GetKind() TriviaKind {
+	return t.kind
+}
+
+func (t Trivia) GetText() string {
+	return t.text
+}
+
+// String converts TriviaKind to its string representation
+func (k TriviaKind) String() string {
+	switch k {
+	case TRIVIA_WHITESPACE:
+		return "TRIVIA_WHITESPACE"
+	case TRIVIA_NEWLINE:
+		return "TRIVIA_NEWLINE"
+	case TRIVIA_COMMENT:
+		return "TRIVIA_COMMENT"
+	case TRIVIA_DOC_COMMENT:
+		return "TRIVIA_DOC_COMMENT"
+	default:
+		return "TRIVIA_UNKNOWN"
+	}
+}
+
+// Function to get the whitespace before a token, split into pieces
+// a token's leading trivia starts after the line of the token before it
+func (t Token) GetLeadingTrivia() []Trivia {
+	return splitTrivia(t.leading)
+}
+
+// Function to get the whitespace after a token, split into pieces
+// it runs up to and including the newline that ends the token's line, and
+// the last token of a file keeps the whitespace left up to the end of the file
+func (t Token) GetTrailingTrivia() []Trivia {
+	return splitTrivia(t.trailing)
+}
+
+// Function to get the source text of a token with its trivia
+// the full text of every token of a file put together is the file
+func (t Token) GetFullText() string {
+	return t.leading + t.lexeme + t.trailing
+}
+
+// Function to read a comment token as trivia
+// false is returned for every token that is not a comment
+func (t Token) AsTrivia() (Trivia, bool) {
+	switch t.token_type {
+	case T_SINGLE_LINE_COMMENT, T_MULTI_LINE_COMMENT:
+		return Trivia{TRIVIA_COMMENT, t.lexeme}, true
+	case T_SINGLE_LINE_DOC_COMMENT, T_MULTI_LINE_DOC_COMMENT:
+		return Trivia{TRIVIA_DOC_COMMENT, t.lexeme}, true
+	}
+	return Trivia{}, false
+}
+
+// splitTrivia splits whitespace into runs of blanks and single newlines
+func splitTrivia(text string) []Trivia {
+	var pieces []Trivia
+	for len(text) > 0 {
+		switch {
+		case text[0] == '\n':
+			pieces = append(pieces, Trivia{TRIVIA_NEWLINE, text[:1]})
+			text = text[1:]
+		case strings.HasPrefix(text, "\r\n"):
+			pieces = append(pieces, Trivia{TRIVIA_NEWLINE, text[:2]})
+			text = text[2:]
+		default:
+			end := 1
+			for end < len(text) && text[end] != '\n' && !strings.HasPrefix(text[end:], "\r\n") {
+				end++
+			}
+			pieces = append(pieces, Trivia{TRIVIA_WHITESPACE, text[:end]})
+			text = text[end:]
+		}
+	}
+	return pieces
+}
+
+// attachTrivia hands the whitespace read since the last token to the tokens around it
+// the last token of the file keeps it up to the end of its line, the new token the rest
+func (l *Lexer) attachTrivia(token *Token) {
+	text := string(l.trivia)
+	l.trivia = l.trivia[:0]
+	if last := len(l.token_stream) - 1; last >= l.first {
+		line := strings.IndexByte(text, '\n') + 1
+		if line == 0 {
+			line = len(text)
+		}
+		l.token_stream[last].trailing += text[:line]
+		text = text[line:]
+	}
+	token.leading = text
+}
+
+// finishTrivia gives the whitespace at the end of a file to its last token
+// a file of nothing but whitespace has no token to hold it, its whitespace is
+// kept as the end trivia of the file
+func (l *Lexer) finishTrivia() {
+	if last := len(l.token_stream) - 1; last >= l.first {
+		l.token_stream[last].trailing += string(l.trivia)
+	} else if len(l.trivia) > 0 {
+		l.end_trivia[l.file] += string(l.trivia)
+	}
+	l.trivia = l.trivia[:0]
+}
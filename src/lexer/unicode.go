@@ -0,0 +1,59 @@
+package lexer
+
+import "unicode"
+
+// characters of ID_Start that are not in XID_Start, and of ID_Continue that are
+// not in XID_Continue. NFKC normalization turns them into text that is not an
+// identifier, which the XID properties exclude
+var (
+	notXIDStart = &unicode.RangeTable{R16: []unicode.Range16{
+		{Lo: 0x037a, Hi: 0x037a, Stride: 1},
+		{Lo: 0x0e33, Hi: 0x0e33, Stride: 1},
+		{Lo: 0x0eb3, Hi: 0x0eb3, Stride: 1},
+		{Lo: 0x309b, Hi: 0x309c, Stride: 1},
+		{Lo: 0xfc5e, Hi: 0xfc63, Stride: 1},
+		{Lo: 0xfdfa, Hi: 0xfdfb, Stride: 1},
+		{Lo: 0xfe70, Hi: 0xfe7e, Stride: 2},
+		{Lo: 0xff9e, Hi: 0xff9f, Stride: 1},
+	}}
+	notXIDContinue = &unicode.RangeTable{R16: []unicode.Range16{
+		{Lo: 0x037a, Hi: 0x037a, Stride: 1},
+		{Lo: 0x309b, Hi: 0x309c, Stride: 1},
+		{Lo: 0xfc5e, Hi: 0xfc63, Stride: 1},
+		{Lo: 0xfdfa, Hi: 0xfdfb, Stride: 1},
+		{Lo: 0xfe70, Hi: 0xfe7e, Stride: 2},
+	}}
+)
+
+// isIdentifierStart checks if a rune can start a unicode identifier
+// _ or an XID_Start character: letters, letter numbers and Other_ID_Start,
+// without pattern syntax and the few characters listed in notXIDStart
+func isIdentifierStart(r rune) bool {
+	if r == '_' {
+		return true
+	}
+	return isIDStart(r) && !unicode.Is(notXIDStart, r)
+}
+
+// isIdentifierContinue checks if a rune can continue a unicode identifier
+// an XID_Continue character: ID_Start plus combining marks, digits and
+// connector punctuation, without the few characters listed in notXIDContinue
+func isIdentifierContinue(r rune) bool {
+	if r == '_' {
+		return true
+	}
+	idContinue := isIDStart(r) ||
+		unicode.In(r, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue)
+	return idContinue && !isPatternCharacter(r) && !unicode.Is(notXIDContinue, r)
+}
+
+// isIDStart checks for the Unicode ID_Start property
+func isIDStart(r rune) bool {
+	return unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start) && !isPatternCharacter(r)
+}
+
+// isPatternCharacter checks for characters Unicode reserves for syntax,
+// which no identifier may contain
+func isPatternCharacter(r rune) bool {
+	return unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space)
+}
@@ -6,14 +6,20 @@ import (
 	"os"
 
 	"github.com/CFdefense/compiler/src/compiler"
+	"github.com/CFdefense/compiler/src/diagnostics"
+	"github.com/CFdefense/compiler/src/lexer"
 	"github.com/CFdefense/compiler/test"
 )
 
 func main() {
 	// initialize command-line flags
-	runTests := flag.String("test", "", "Run compiler test suite (e.g., lexer)")
+	runTests := flag.String("test", "", "Run compiler test suite (e.g., lexer, parser, regex, constant, asm)")
 	targetPath := flag.String("path", "", "Directory to compile")
 	debugMode := flag.Bool("debug", false, "Enable verbose debug mode")
+	dumpAutomata := flag.String("dump-automata", "", "Write Graphviz .dot files of the lexer automata to a directory, the automata of each lexer mode go to a subdirectory")
+	unicodeIdentifiers := flag.Bool("unicode-identifiers", false, "Allow Unicode letters in identifiers")
+	legacyComments := flag.Bool("legacy-comments", false, "Read // at the start of a line as a comment and warn about it")
+	columns := flag.String("columns", "runes", "Count source columns in runes or bytes")
 
 	// parse the inputted command-line flags
 	flag.Parse()
@@ -24,6 +30,14 @@ func main() {
 		switch *runTests {
 		case "lexer":
 			test.RunTests(*debugMode)
+		case "parser":
+			test.RunParserTests(*debugMode)
+		case "regex":
+			test.RunRegexTests(*debugMode)
+		case "constant":
+			test.RunConstantTests(*debugMode)
+		case "asm":
+			test.RunAsmTests(*debugMode)
 		default:
 			log.Printf("Unknown test target: %s\n", *runTests)
 			os.Exit(1)
@@ -32,6 +46,18 @@ func main() {
 		return
 	}
 
+	// write the lexer automata if requested, on its own it needs no path
+	if *dumpAutomata != "" {
+		if err := lexer.CompiledAutomata().DumpAutomata(*dumpAutomata); err != nil {
+			log.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		log.Printf("Lexer automata written to: %s\n", *dumpAutomata)
+		if *targetPath == "" {
+			return
+		}
+	}
+
 	// ensure a path is provided if were not running tests
 	if *runTests == "" && *targetPath == "" {
 		log.Println("Error: no path provided. Use -path to specify source directory.")
@@ -44,9 +70,34 @@ func main() {
 
 	// create the compiler ctx
 	compiler_ctx := compiler.InitializeCompiler(*debugMode)
+	compiler_ctx.SetUnicodeIdentifiers(*unicodeIdentifiers)
+	compiler_ctx.SetLegacyComments(*legacyComments)
+	switch *columns {
+	case "runes":
+		compiler_ctx.SetColumnUnit(diagnostics.COLUMN_RUNES)
+	case "bytes":
+		compiler_ctx.SetColumnUnit(diagnostics.COLUMN_BYTES)
+	default:
+		log.Printf("Unknown column unit: %s\n", *columns)
+		os.Exit(1)
+	}
 
 	// start lexical analysis
 	compiler_ctx.BeginLexicalAnalysis(*targetPath)
 
+	// start syntax analysis
+	compiler_ctx.BeginSyntaxAnalysis()
+
+	// fold constant expressions
+	compiler_ctx.BeginConstantEvaluation()
+
+	// check inline assembly
+	compiler_ctx.BeginAsmValidation()
+
+	// report everything found so far and stop on errors
+	if compiler_ctx.EmitDiagnostics() {
+		os.Exit(1)
+	}
+
 	// TODO: Next compiler steps
 }
@@ -0,0 +1,730 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/CFdefense/compiler/src/lexer"
+)
+
+// Every node in the abstract syntax tree
+// String renders the node as an s-expression which
+// is what the parser test suite compares against
+type Node interface {
+	GetToken() lexer.Token
+	String() string
+}
+
+// Top level objects (functions, enums, structs, consts)
+type Declaration interface {
+	Node
+	declarationNode()
+}
+
+// Anything that can appear inside of a block
+type Statement interface {
+	Node
+	statementNode()
+}
+
+// Anything that produces a value
+type Expression interface {
+	Node
+	expressionNode()
+}
+
+// Root of the tree, one per compilation
+type Program struct {
+	Declarations []Declaration
+}
+
+func (p *Program) GetToken() lexer.Token {
+	if len(p.Declarations) > 0 {
+		return p.Declarations[0].GetToken()
+	}
+	return lexer.Token{}
+}
+
+func (p *Program) String() string {
+	parts := []string{"program"}
+	for _, decl := range p.Declarations {
+		parts = append(parts, decl.String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Complex object that failed to parse, Token is where it started
+type ErrorDecl struct {
+	Token lexer.Token
+}
+
+func (e *ErrorDecl) GetToken() lexer.Token { return e.Token }
+func (e *ErrorDecl) declarationNode()      {}
+func (e *ErrorDecl) String() string        { return "(error)" }
+
+// Decorator attached to a complex object (@inline, @unsafe)
+type Decorator struct {
+	Token lexer.Token
+	Name  string
+}
+
+func (d *Decorator) GetToken() lexer.Token { return d.Token }
+func (d *Decorator) String() string        { return "@" + d.Name }
+
+// Type reference such as int, bool, void*, Point
+type TypeNode struct {
+	Token    lexer.Token
+	Name     string
+	Pointers int
+}
+
+func (t *TypeNode) GetToken() lexer.Token { return t.Token }
+func (t *TypeNode) String() string        { return t.Name + strings.Repeat("*", t.Pointers) }
+
+// Declarator names a variable or parameter and carries
+// its pointer, array and function pointer shape
+type Declarator struct {
+	Token         lexer.Token
+	Name          string
+	Pointers      int
+	ArrayDims     []Expression // nil entry for an unsized dimension
+	IsFuncPointer bool
+	FuncParams    []*Param
+	Init          Expression
+}
+
+func (d *Declarator) GetToken() lexer.Token { return d.Token }
+
+func (d *Declarator) String() string {
+	var sb strings.Builder
+	sb.WriteString(strings.Repeat("*", d.Pointers))
+	if d.IsFuncPointer {
+		sb.WriteString("(*" + d.Name + ")" + paramsString(d.FuncParams))
+	} else {
+		sb.WriteString(d.Name)
+	}
+	for _, dim := range d.ArrayDims {
+		if dim == nil {
+			sb.WriteString("[]")
+		} else {
+			sb.WriteString("[" + dim.String() + "]")
+		}
+	}
+	if d.Init != nil {
+		return "(= " + sb.String() + " " + d.Init.String() + ")"
+	}
+	return sb.String()
+}
+
+// Function parameter
+type Param struct {
+	Token      lexer.Token
+	Type       *TypeNode
+	Mutable    bool
+	Declarator *Declarator
+}
+
+func (p *Param) GetToken() lexer.Token { return p.Token }
+
+func (p *Param) String() string {
+	parts := []string{"param", p.Type.String()}
+	if p.Mutable {
+		parts = append(parts, "mut")
+	}
+	if p.Declarator.Name != "" {
+		parts = append(parts, p.Declarator.String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+func paramsString(params []*Param) string {
+	parts := []string{"params"}
+	for _, param := range params {
+		parts = append(parts, param.String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// docString renders the doc comment of a node, nothing when it has none
+func docString(doc string) []string {
+	if doc == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("(doc %q)", doc)}
+}
+
+func decoratorsString(decorators []*Decorator) []string {
+	var parts []string
+	for _, decorator := range decorators {
+		parts = append(parts, decorator.String())
+	}
+	return parts
+}
+
+// Function definition
+type FunctionDecl struct {
+	Token      lexer.Token
+	Doc        string
+	Decorators []*Decorator
+	ReturnType *TypeNode
+	Name       string
+	Params     []*Param
+	Body       *Block
+}
+
+func (f *FunctionDecl) GetToken() lexer.Token { return f.Token }
+func (f *FunctionDecl) declarationNode()      {}
+
+func (f *FunctionDecl) String() string {
+	parts := append([]string{"function"}, docString(f.Doc)...)
+	parts = append(parts, decoratorsString(f.Decorators)...)
+	parts = append(parts, f.ReturnType.String(), f.Name, paramsString(f.Params), f.Body.String())
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Single variant of an enum, optionally with a payload or value
+type EnumVariant struct {
+	Token  lexer.Token
+	Doc    string
+	Name   string
+	Fields []*TypeNode
+	Value  Expression
+}
+
+func (v *EnumVariant) GetToken() lexer.Token { return v.Token }
+
+func (v *EnumVariant) String() string {
+	if len(v.Fields) == 0 && v.Value == nil && v.Doc == "" {
+		return v.Name
+	}
+	parts := append([]string{v.Name}, docString(v.Doc)...)
+	for _, field := range v.Fields {
+		parts = append(parts, field.String())
+	}
+	if v.Value != nil {
+		parts = append(parts, "=", v.Value.String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Enum definition
+type EnumDecl struct {
+	Token      lexer.Token
+	Doc        string
+	Decorators []*Decorator
+	Name       string
+	Variants   []*EnumVariant
+}
+
+func (e *EnumDecl) GetToken() lexer.Token { return e.Token }
+func (e *EnumDecl) declarationNode()      {}
+
+func (e *EnumDecl) String() string {
+	parts := append([]string{"enum"}, docString(e.Doc)...)
+	parts = append(parts, decoratorsString(e.Decorators)...)
+	parts = append(parts, e.Name)
+	for _, variant := range e.Variants {
+		parts = append(parts, variant.String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Struct definition
+type StructDecl struct {
+	Token      lexer.Token
+	Doc        string
+	Decorators []*Decorator
+	Name       string
+	Fields     []*VarDecl
+}
+
+func (s *StructDecl) GetToken() lexer.Token { return s.Token }
+func (s *StructDecl) declarationNode()      {}
+
+func (s *StructDecl) String() string {
+	parts := append([]string{"struct"}, docString(s.Doc)...)
+	parts = append(parts, decoratorsString(s.Decorators)...)
+	parts = append(parts, s.Name)
+	for _, field := range s.Fields {
+		parts = append(parts, field.String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Constant definition
+type ConstDecl struct {
+	Token      lexer.Token
+	Doc        string
+	Decorators []*Decorator
+	Type       *TypeNode
+	Name       string
+	Value      Expression
+}
+
+func (c *ConstDecl) GetToken() lexer.Token { return c.Token }
+func (c *ConstDecl) declarationNode()      {}
+
+func (c *ConstDecl) String() string {
+	parts := append([]string{"const"}, docString(c.Doc)...)
+	parts = append(parts, decoratorsString(c.Decorators)...)
+	parts = append(parts, c.Type.String(), c.Name, c.Value.String())
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Block of statements with an optional trailing result expression
+// blocks are also expressions (block_expr in the grammar)
+type Block struct {
+	Token      lexer.Token
+	Statements []Statement
+	Result     Expression
+}
+
+func (b *Block) GetToken() lexer.Token { return b.Token }
+func (b *Block) statementNode()        {}
+func (b *Block) expressionNode()       {}
+
+func (b *Block) String() string {
+	parts := []string{"block"}
+	for _, stmt := range b.Statements {
+		parts = append(parts, stmt.String())
+	}
+	if b.Result != nil {
+		parts = append(parts, "(result "+b.Result.String()+")")
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Variable declaration, also used for struct fields
+type VarDecl struct {
+	Token       lexer.Token
+	Doc         string // only struct fields are documented
+	Type        *TypeNode
+	Mutable     bool
+	Declarators []*Declarator
+}
+
+func (v *VarDecl) GetToken() lexer.Token { return v.Token }
+func (v *VarDecl) statementNode()        {}
+
+func (v *VarDecl) String() string {
+	parts := append([]string{"var"}, docString(v.Doc)...)
+	parts = append(parts, v.Type.String())
+	if v.Mutable {
+		parts = append(parts, "mut")
+	}
+	for _, declarator := range v.Declarators {
+		parts = append(parts, declarator.String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Expression used as a statement
+type ExprStatement struct {
+	Token lexer.Token
+	Expr  Expression
+}
+
+func (e *ExprStatement) GetToken() lexer.Token { return e.Token }
+func (e *ExprStatement) statementNode()        {}
+func (e *ExprStatement) String() string        { return e.Expr.String() }
+
+// label: statement
+type LabeledStatement struct {
+	Token lexer.Token
+	Label string
+	Body  Statement
+}
+
+func (l *LabeledStatement) GetToken() lexer.Token { return l.Token }
+func (l *LabeledStatement) statementNode()        {}
+
+func (l *LabeledStatement) String() string {
+	return fmt.Sprintf("(label %s %s)", l.Label, l.Body.String())
+}
+
+// if statement, Else is either a *Block or an *IfStatement
+type IfStatement struct {
+	Token     lexer.Token
+	Condition Expression
+	Then      *Block
+	Else      Statement
+}
+
+func (i *IfStatement) GetToken() lexer.Token { return i.Token }
+func (i *IfStatement) statementNode()        {}
+
+func (i *IfStatement) String() string {
+	if i.Else == nil {
+		return fmt.Sprintf("(if %s %s)", i.Condition.String(), i.Then.String())
+	}
+	return fmt.Sprintf("(if %s %s %s)", i.Condition.String(), i.Then.String(), i.Else.String())
+}
+
+// while loop
+type WhileStatement struct {
+	Token     lexer.Token
+	Condition Expression
+	Body      *Block
+}
+
+func (w *WhileStatement) GetToken() lexer.Token { return w.Token }
+func (w *WhileStatement) statementNode()        {}
+
+func (w *WhileStatement) String() string {
+	return fmt.Sprintf("(while %s %s)", w.Condition.String(), w.Body.String())
+}
+
+// do { } while (cond);
+type DoWhileStatement struct {
+	Token     lexer.Token
+	Body      *Block
+	Condition Expression
+}
+
+func (d *DoWhileStatement) GetToken() lexer.Token { return d.Token }
+func (d *DoWhileStatement) statementNode()        {}
+
+func (d *DoWhileStatement) String() string {
+	return fmt.Sprintf("(do %s %s)", d.Body.String(), d.Condition.String())
+}
+
+// for (init; cond; update) { }, every clause is optional
+type ForStatement struct {
+	Token     lexer.Token
+	Init      Statement
+	Condition Expression
+	Update    Expression
+	Body      *Block
+}
+
+func (f *ForStatement) GetToken() lexer.Token { return f.Token }
+func (f *ForStatement) statementNode()        {}
+
+func (f *ForStatement) String() string {
+	return fmt.Sprintf("(for %s %s %s %s)", optionalString(f.Init), optionalString(f.Condition),
+		optionalString(f.Update), f.Body.String())
+}
+
+// Single arm of a match, Pattern is nil for the wildcard arm
+type MatchArm struct {
+	Token   lexer.Token
+	Pattern Expression
+	Body    Node
+}
+
+func (m *MatchArm) GetToken() lexer.Token { return m.Token }
+
+func (m *MatchArm) String() string {
+	pattern := "_"
+	if m.Pattern != nil {
+		pattern = m.Pattern.String()
+	}
+	return fmt.Sprintf("(arm %s %s)", pattern, m.Body.String())
+}
+
+// match statement
+type MatchStatement struct {
+	Token   lexer.Token
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+func (m *MatchStatement) GetToken() lexer.Token { return m.Token }
+func (m *MatchStatement) statementNode()        {}
+
+func (m *MatchStatement) String() string {
+	parts := []string{"match", optionalString(m.Subject)}
+	for _, arm := range m.Arms {
+		parts = append(parts, arm.String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// One line of inline assembly, either a string literal
+// or the raw tokens that made up an instruction line
+type AsmLine struct {
+	Token  lexer.Token
+	Text   string
+	Tokens []lexer.Token
+}
+
+func (a *AsmLine) GetToken() lexer.Token { return a.Token }
+
+func (a *AsmLine) String() string {
+	if a.Tokens == nil {
+		return a.Text
+	}
+	parts := make([]string, 0, len(a.Tokens))
+	for _, token := range a.Tokens {
+		parts = append(parts, token.GetTokenContent())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Operand of extended asm, a constraint string binding a Sea expression as in "=r"(x)
+type AsmOperand struct {
+	Token      lexer.Token
+	Constraint string
+	Expr       Expression
+}
+
+func (a *AsmOperand) GetToken() lexer.Token { return a.Token }
+
+func (a *AsmOperand) String() string {
+	return "(" + a.Token.GetTokenContent() + " " + a.Expr.String() + ")"
+}
+
+// asm("...") or asm { ... }
+// extended asm has a single template line followed by its outputs, inputs and clobbers
+type AsmStatement struct {
+	Token    lexer.Token
+	Lines    []*AsmLine
+	Extended bool
+	Outputs  []*AsmOperand
+	Inputs   []*AsmOperand
+	Clobbers []lexer.Token
+}
+
+func (a *AsmStatement) GetToken() lexer.Token { return a.Token }
+func (a *AsmStatement) statementNode()        {}
+
+func (a *AsmStatement) String() string {
+	parts := []string{"asm"}
+	for _, line := range a.Lines {
+		parts = append(parts, line.String())
+	}
+	if !a.Extended {
+		return "(" + strings.Join(parts, " ") + ")"
+	}
+	outputs, inputs, clobbers := []string{"outputs"}, []string{"inputs"}, []string{"clobbers"}
+	for _, output := range a.Outputs {
+		outputs = append(outputs, output.String())
+	}
+	for _, input := range a.Inputs {
+		inputs = append(inputs, input.String())
+	}
+	for _, clobber := range a.Clobbers {
+		clobbers = append(clobbers, clobber.GetTokenContent())
+	}
+	parts = append(parts, "("+strings.Join(outputs, " ")+")", "("+strings.Join(inputs, " ")+")",
+		"("+strings.Join(clobbers, " ")+")")
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Statement that failed to parse, Token is where it started
+type ErrorStatement struct {
+	Token lexer.Token
+}
+
+func (e *ErrorStatement) GetToken() lexer.Token { return e.Token }
+func (e *ErrorStatement) statementNode()        {}
+func (e *ErrorStatement) String() string        { return "(error)" }
+
+// return expr?;
+type ReturnStatement struct {
+	Token lexer.Token
+	Value Expression
+}
+
+func (r *ReturnStatement) GetToken() lexer.Token { return r.Token }
+func (r *ReturnStatement) statementNode()        {}
+
+func (r *ReturnStatement) String() string {
+	if r.Value == nil {
+		return "(return)"
+	}
+	return "(return " + r.Value.String() + ")"
+}
+
+// break;
+type BreakStatement struct {
+	Token lexer.Token
+}
+
+func (b *BreakStatement) GetToken() lexer.Token { return b.Token }
+func (b *BreakStatement) statementNode()        {}
+func (b *BreakStatement) String() string        { return "(break)" }
+
+// continue;
+type ContinueStatement struct {
+	Token lexer.Token
+}
+
+func (c *ContinueStatement) GetToken() lexer.Token { return c.Token }
+func (c *ContinueStatement) statementNode()        {}
+func (c *ContinueStatement) String() string        { return "(continue)" }
+
+// Variable, function or constant reference
+type Identifier struct {
+	Token lexer.Token
+	Name  string
+}
+
+func (i *Identifier) GetToken() lexer.Token { return i.Token }
+func (i *Identifier) expressionNode()       {}
+func (i *Identifier) String() string        { return i.Name }
+
+// Integer, bool, string and char literals
+// Kind is the lexer token type that produced the literal
+type Literal struct {
+	Token lexer.Token
+	Kind  lexer.TokenType
+	Value string
+}
+
+func (l *Literal) GetToken() lexer.Token { return l.Token }
+func (l *Literal) expressionNode()       {}
+func (l *Literal) String() string        { return l.Value }
+
+// Prefix or postfix unary operation
+type UnaryExpr struct {
+	Token   lexer.Token
+	Op      string
+	Operand Expression
+	Postfix bool
+}
+
+func (u *UnaryExpr) GetToken() lexer.Token { return u.Token }
+func (u *UnaryExpr) expressionNode()       {}
+
+func (u *UnaryExpr) String() string {
+	if u.Postfix {
+		return fmt.Sprintf("(%s %s)", u.Operand.String(), u.Op)
+	}
+	return fmt.Sprintf("(%s %s)", u.Op, u.Operand.String())
+}
+
+// Binary operation
+type BinaryExpr struct {
+	Token lexer.Token
+	Op    string
+	Left  Expression
+	Right Expression
+}
+
+func (b *BinaryExpr) GetToken() lexer.Token { return b.Token }
+func (b *BinaryExpr) expressionNode()       {}
+
+func (b *BinaryExpr) String() string {
+	return fmt.Sprintf("(%s %s %s)", b.Op, b.Left.String(), b.Right.String())
+}
+
+// Plain and compound assignment
+type AssignExpr struct {
+	Token  lexer.Token
+	Op     string
+	Target Expression
+	Value  Expression
+}
+
+func (a *AssignExpr) GetToken() lexer.Token { return a.Token }
+func (a *AssignExpr) expressionNode()       {}
+
+func (a *AssignExpr) String() string {
+	return fmt.Sprintf("(%s %s %s)", a.Op, a.Target.String(), a.Value.String())
+}
+
+// Function call
+type CallExpr struct {
+	Token     lexer.Token
+	Callee    Expression
+	Arguments []Expression
+}
+
+func (c *CallExpr) GetToken() lexer.Token { return c.Token }
+func (c *CallExpr) expressionNode()       {}
+
+func (c *CallExpr) String() string {
+	parts := []string{"call", c.Callee.String()}
+	for _, arg := range c.Arguments {
+		parts = append(parts, arg.String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Array access
+type IndexExpr struct {
+	Token lexer.Token
+	Array Expression
+	Index Expression
+}
+
+func (i *IndexExpr) GetToken() lexer.Token { return i.Token }
+func (i *IndexExpr) expressionNode()       {}
+
+func (i *IndexExpr) String() string {
+	return fmt.Sprintf("(index %s %s)", i.Array.String(), i.Index.String())
+}
+
+// Member access through . or ->
+type MemberExpr struct {
+	Token  lexer.Token
+	Object Expression
+	Member string
+	Arrow  bool
+}
+
+func (m *MemberExpr) GetToken() lexer.Token { return m.Token }
+func (m *MemberExpr) expressionNode()       {}
+
+func (m *MemberExpr) String() string {
+	op := "."
+	if m.Arrow {
+		op = "->"
+	}
+	return fmt.Sprintf("(%s %s %s)", op, m.Object.String(), m.Member)
+}
+
+// Conditional expression: cond ? then : else
+type TernaryExpr struct {
+	Token     lexer.Token
+	Condition Expression
+	Then      Expression
+	Else      Expression
+}
+
+func (t *TernaryExpr) GetToken() lexer.Token { return t.Token }
+func (t *TernaryExpr) expressionNode()       {}
+
+func (t *TernaryExpr) String() string {
+	return fmt.Sprintf("(? %s %s %s)", t.Condition.String(), t.Then.String(), t.Else.String())
+}
+
+// Type cast: (type)expr
+type CastExpr struct {
+	Token   lexer.Token
+	Type    *TypeNode
+	Operand Expression
+}
+
+func (c *CastExpr) GetToken() lexer.Token { return c.Token }
+func (c *CastExpr) expressionNode()       {}
+
+func (c *CastExpr) String() string {
+	return fmt.Sprintf("(cast %s %s)", c.Type.String(), c.Operand.String())
+}
+
+// sizeof(type) or sizeof(expr), exactly one of Type and Operand is set
+type SizeofExpr struct {
+	Token   lexer.Token
+	Type    *TypeNode
+	Operand Expression
+}
+
+func (s *SizeofExpr) GetToken() lexer.Token { return s.Token }
+func (s *SizeofExpr) expressionNode()       {}
+
+func (s *SizeofExpr) String() string {
+	if s.Type != nil {
+		return "(sizeof " + s.Type.String() + ")"
+	}
+	return "(sizeof " + s.Operand.String() + ")"
+}
+
+// renders an optional node, using _ when it is absent
+func optionalString(node Node) string {
+	if node == nil {
+		return "_"
+	}
+	return node.String()
+}
@@ -0,0 +1,257 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/CFdefense/compiler/src/lexer"
+)
+
+// The concrete syntax tree is built next to the AST while parsing
+// it keeps every token of the source with the trivia around it, the whitespace
+// and comments the AST drops, so the text of the tree is the source it was parsed from
+// formatters, refactoring tools and editors work on it instead of the AST
+
+// What a node of the concrete syntax tree is
+type SyntaxKind int
+
+const (
+	CST_PROGRAM SyntaxKind = iota
+	CST_FUNCTION
+	CST_ENUM
+	CST_VARIANT
+	CST_STRUCT
+	CST_CONST
+	CST_DECORATOR
+	CST_TYPE
+	CST_PARAMS
+	CST_PARAM
+	CST_DECLARATOR
+	CST_BLOCK
+	CST_VAR_DECL
+	CST_EXPR_STATEMENT
+	CST_LABELED
+	CST_IF
+	CST_WHILE
+	CST_DO_WHILE
+	CST_FOR
+	CST_MATCH
+	CST_ARM
+	CST_ASM
+	CST_ASM_LINE
+	CST_ASM_OPERAND
+	CST_RETURN
+	CST_BREAK
+	CST_CONTINUE
+	CST_UNARY
+	CST_BINARY
+	CST_ASSIGN
+	CST_TERNARY
+	CST_CALL
+	CST_INDEX
+	CST_MEMBER
+	CST_CAST
+	CST_SIZEOF
+	CST_PAREN
+	CST_ERROR // what was skipped over after a syntax error
+)
+
+var syntaxKindNames = map[SyntaxKind]string{
+	CST_PROGRAM:        "program",
+	CST_FUNCTION:       "function",
+	CST_ENUM:           "enum",
+	CST_VARIANT:        "variant",
+	CST_STRUCT:         "struct",
+	CST_CONST:          "const",
+	CST_DECORATOR:      "decorator",
+	CST_TYPE:           "type",
+	CST_PARAMS:         "params",
+	CST_PARAM:          "param",
+	CST_DECLARATOR:     "declarator",
+	CST_BLOCK:          "block",
+	CST_VAR_DECL:       "var",
+	CST_EXPR_STATEMENT: "expr",
+	CST_LABELED:        "label",
+	CST_IF:             "if",
+	CST_WHILE:          "while",
+	CST_DO_WHILE:       "do",
+	CST_FOR:            "for",
+	CST_MATCH:          "match",
+	CST_ARM:            "arm",
+	CST_ASM:            "asm",
+	CST_ASM_LINE:       "line",
+	CST_ASM_OPERAND:    "operand",
+	CST_RETURN:         "return",
+	CST_BREAK:          "break",
+	CST_CONTINUE:       "continue",
+	CST_UNARY:          "unary",
+	CST_BINARY:         "binary",
+	CST_ASSIGN:         "assign",
+	CST_TERNARY:        "ternary",
+	CST_CALL:           "call",
+	CST_INDEX:          "index",
+	CST_MEMBER:         "member",
+	CST_CAST:           "cast",
+	CST_SIZEOF:         "sizeof",
+	CST_PAREN:          "paren",
+	CST_ERROR:          "error",
+}
+
+func (k SyntaxKind) String() string {
+	if name, ok := syntaxKindNames[k]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// SyntaxElement is a node or a token of the concrete syntax tree
+// Text is the source the element was parsed from, trivia included
+type SyntaxElement interface {
+	Text() string
+	String() string
+}
+
+// SyntaxNode is a node of the concrete syntax tree, its children in source order
+type SyntaxNode struct {
+	Kind     SyntaxKind
+	Children []SyntaxElement
+}
+
+func (n *SyntaxNode) Text() string {
+	var text strings.Builder
+	for _, child := range n.Children {
+		text.WriteString(child.Text())
+	}
+	return text.String()
+}
+
+func (n *SyntaxNode) String() string {
+	parts := []string{n.Kind.String()}
+	for _, child := range n.Children {
+		parts = append(parts, child.String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// SyntaxToken is a leaf of the concrete syntax tree
+// trailing trivia runs up to the end of the token's line, leading trivia is the
+// rest of what comes before it. a file of nothing but comments and whitespace
+// ends in a token with no text that holds them
+type SyntaxToken struct {
+	Token    lexer.Token
+	Leading  []lexer.Trivia
+	Trailing []lexer.Trivia
+}
+
+func (t *SyntaxToken) Text() string {
+	var text strings.Builder
+	for _, trivia := range t.Leading {
+		text.WriteString(trivia.GetText())
+	}
+	text.WriteString(t.Token.GetTokenContent())
+	for _, trivia := range t.Trailing {
+		text.WriteString(trivia.GetText())
+	}
+	return text.String()
+}
+
+// a token is written quoted, with its trivia in brackets right before and after it
+// as in ["\n" "    "]"return"[" "]
+func (t *SyntaxToken) String() string {
+	return triviaString(t.Leading) + strconv.Quote(t.Token.GetTokenContent()) + triviaString(t.Trailing)
+}
+
+func triviaString(trivia []lexer.Trivia) string {
+	if len(trivia) == 0 {
+		return ""
+	}
+	parts := make([]string, len(trivia))
+	for i, piece := range trivia {
+		parts[i] = strconv.Quote(piece.GetText())
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+// trivia of a token with the comments around it
+type tokenTrivia struct {
+	leading  []lexer.Trivia
+	trailing []lexer.Trivia
+}
+
+// Function to get the concrete syntax tree of the last analysis
+func (p *Parser) GetCST() *SyntaxNode {
+	return p.cst
+}
+
+// collectTrivia sets the parser's tokens and the trivia of each of them
+// comments become trivia, a comment on the line of the token before it is
+// that token's trailing trivia and any other is leading trivia of the next token
+// comments after the last token are its trailing trivia as well, a file without
+// tokens keeps its comments and whitespace at its end
+func (p *Parser) collectTrivia(tokens []lexer.Token) {
+	var pending []lexer.Trivia
+	for _, token := range tokens {
+		comment, ok := token.AsTrivia()
+		if !ok {
+			leading := append(pending, token.GetLeadingTrivia()...)
+			p.tokens = append(p.tokens, token)
+			p.trivia = append(p.trivia, tokenTrivia{leading, token.GetTrailingTrivia()})
+			pending = nil
+			continue
+		}
+
+		pieces := append(token.GetLeadingTrivia(), comment)
+		pieces = append(pieces, token.GetTrailingTrivia()...)
+		last := len(p.trivia) - 1
+		if last >= 0 && len(pending) == 0 && !endsLine(p.trivia[last].trailing) {
+			p.trivia[last].trailing = append(p.trivia[last].trailing, pieces...)
+		} else {
+			pending = append(pending, pieces...)
+		}
+	}
+
+	switch {
+	case len(p.trivia) > 0:
+		last := len(p.trivia) - 1
+		p.trivia[last].trailing = append(p.trivia[last].trailing, pending...)
+	case len(pending)+len(p.endTrivia) > 0:
+		p.end = append(pending, p.endTrivia...)
+	}
+}
+
+// endsLine checks if trivia holds a newline
+func endsLine(trivia []lexer.Trivia) bool {
+	for _, piece := range trivia {
+		if piece.GetKind() == lexer.TRIVIA_NEWLINE {
+			return true
+		}
+	}
+	return false
+}
+
+// shift adds the token at pos to the tree as a leaf
+// every consumed token is shifted, in order, by advance
+func (p *Parser) shift(pos int) {
+	p.syntax = append(p.syntax, &SyntaxToken{
+		Token:    p.tokens[pos],
+		Leading:  p.trivia[pos].leading,
+		Trailing: p.trivia[pos].trailing,
+	})
+}
+
+// mark returns where the tree of what is parsed next starts
+func (p *Parser) mark() int {
+	return len(p.syntax)
+}
+
+// wrap turns everything added to the tree since mark into a node
+// called once a node is parsed, and by defer in the functions that parse a single
+// node, so that what was parsed before a syntax error still ends up as a node
+// a node without any tokens, as the parameters of f(), is left out
+func (p *Parser) wrap(mark int, kind SyntaxKind) {
+	if mark == len(p.syntax) {
+		return
+	}
+	node := &SyntaxNode{Kind: kind, Children: append([]SyntaxElement{}, p.syntax[mark:]...)}
+	p.syntax = append(p.syntax[:mark], node)
+}
@@ -0,0 +1,357 @@
+package parser
+
+import (
+	"github.com/CFdefense/compiler/src/lexer"
+)
+
+// Operator precedence, loosest to tightest
+//
+//	level  operators                              associativity
+//	-----  -------------------------------------  -------------
+//	1      ,                                      left
+//	2      = += -= *= /= //= %= <<= >>= &= ^= |=  right
+//	3      ?:                                     right
+//	4      ||                                     left
+//	5      &&                                     left
+//	6      |                                      left
+//	7      ^                                      left
+//	8      &                                      left
+//	9      == !=                                  left
+//	10     < > <= >=                              left
+//	11     << >>                                  left
+//	12     + -                                    left
+//	13     * / // %                               left
+//	14     prefix ++ -- + - ! ~ * & &mut,
+//	       (type) casts, sizeof                   right
+//	15     postfix () [] . -> ++ --               left
+//
+// levels 1 through 13 are handled by precedence climbing in parseBinary,
+// levels 14 and 15 by parseUnary and parsePostfix
+const (
+	PREC_LOWEST = iota
+	PREC_COMMA
+	PREC_ASSIGN
+	PREC_TERNARY
+	PREC_LOGICAL_OR
+	PREC_LOGICAL_AND
+	PREC_BITWISE_OR
+	PREC_BITWISE_XOR
+	PREC_BITWISE_AND
+	PREC_EQUALITY
+	PREC_RELATIONAL
+	PREC_SHIFT
+	PREC_ADDITIVE
+	PREC_MULTIPLICATIVE
+)
+
+// binary operator precedence keyed by token type, anything else ends a binary expression
+var binaryPrecedence = map[lexer.TokenType]int{
+	lexer.T_COMMA:         PREC_COMMA,
+	lexer.T_QUESTION:      PREC_TERNARY,
+	lexer.T_OR:            PREC_LOGICAL_OR,
+	lexer.T_AND:           PREC_LOGICAL_AND,
+	lexer.T_PIPE:          PREC_BITWISE_OR,
+	lexer.T_XOR:           PREC_BITWISE_XOR,
+	lexer.T_AMPERSAND:     PREC_BITWISE_AND,
+	lexer.T_EQUALS:        PREC_EQUALITY,
+	lexer.T_NOT_EQUALS:    PREC_EQUALITY,
+	lexer.T_LESS_THAN:     PREC_RELATIONAL,
+	lexer.T_GREATER_THAN:  PREC_RELATIONAL,
+	lexer.T_LESS_EQUAL:    PREC_RELATIONAL,
+	lexer.T_GREATER_EQUAL: PREC_RELATIONAL,
+	lexer.T_LEFT_SHIFT:    PREC_SHIFT,
+	lexer.T_RIGHT_SHIFT:   PREC_SHIFT,
+	lexer.T_PLUS:          PREC_ADDITIVE,
+	lexer.T_MINUS:         PREC_ADDITIVE,
+	lexer.T_MULTIPLY:      PREC_MULTIPLICATIVE,
+	lexer.T_DIVIDE:        PREC_MULTIPLICATIVE,
+	lexer.T_INT_DIVIDE:    PREC_MULTIPLICATIVE,
+	lexer.T_MODULO:        PREC_MULTIPLICATIVE,
+}
+
+// parseExpression parses a full expression including the comma operator
+func (p *Parser) parseExpression() Expression {
+	return p.parseBinary(PREC_COMMA)
+}
+
+// parseAssignment parses an expression that stops at a top level comma
+// used for call arguments, initializers and match arms where , is a separator
+func (p *Parser) parseAssignment() Expression {
+	return p.parseBinary(PREC_ASSIGN)
+}
+
+// parseBinary is the precedence climbing loop
+// it keeps folding operators into left for as long as
+// they bind at least as tightly as minPrec
+func (p *Parser) parseBinary(minPrec int) Expression {
+	mark := p.mark()
+	left := p.parseUnary()
+
+	for {
+		// assignment = unary_expr assign expr (right associative)
+		if op, width := p.assignOperator(); width > 0 {
+			if PREC_ASSIGN < minPrec {
+				return left
+			}
+			token := p.peek()
+			for range width {
+				p.advance()
+			}
+			value := p.parseBinary(PREC_ASSIGN)
+			left = &AssignExpr{Token: token, Op: op, Target: left, Value: value}
+			p.wrap(mark, CST_ASSIGN)
+			continue
+		}
+
+		op, prec := p.binaryOperator()
+		if prec == PREC_LOWEST || prec < minPrec {
+			return left
+		}
+		token := p.advance()
+
+		// conditional_expr = expr "?" expr ":" expr (right associative)
+		if op == "?" {
+			then := p.parseExpression()
+			p.expect(lexer.T_COLON, "`:` in conditional expression")
+			otherwise := p.parseBinary(PREC_TERNARY)
+			left = &TernaryExpr{Token: token, Condition: left, Then: then, Else: otherwise}
+			p.wrap(mark, CST_TERNARY)
+			continue
+		}
+
+		// every remaining operator is left associative
+		right := p.parseBinary(prec + 1)
+		left = &BinaryExpr{Token: token, Op: op, Left: left, Right: right}
+		p.wrap(mark, CST_BINARY)
+	}
+}
+
+// binaryOperator reports the binary operator at the current position and its precedence
+// compound assignments and doubled ++ / -- are not binary operators
+func (p *Parser) binaryOperator() (string, int) {
+	token := p.peek()
+	prec, ok := binaryPrecedence[token.GetTokenType()]
+	if p.atEnd() || !ok {
+		return "", PREC_LOWEST
+	}
+	if _, width := p.assignOperator(); width > 0 {
+		return "", PREC_LOWEST
+	}
+	return token.GetTokenContent(), prec
+}
+
+// assignOperator reports the assignment operator at the current position
+// and how many tokens it spans, the lexer emits x += 1 as x + = 1
+func (p *Parser) assignOperator() (string, int) {
+	token := p.peek()
+	if token.GetTokenType() == lexer.T_ASSIGN {
+		return "=", 1
+	}
+	switch token.GetTokenType() {
+	case lexer.T_PLUS, lexer.T_MINUS, lexer.T_MULTIPLY, lexer.T_DIVIDE, lexer.T_INT_DIVIDE, lexer.T_MODULO,
+		lexer.T_LEFT_SHIFT, lexer.T_RIGHT_SHIFT, lexer.T_AMPERSAND, lexer.T_PIPE, lexer.T_XOR:
+		next := p.peekAt(1)
+		if next.GetTokenType() == lexer.T_ASSIGN && adjacent(token, next) {
+			return token.GetTokenContent() + "=", 2
+		}
+	}
+	return "", 0
+}
+
+// unary_expr = "++" expr | "--" expr | "+" expr | "-" expr | "~" expr | "!" expr ;
+// pointer_expr = "*" expr | "&" expr | "&" "mut" expr ;
+// cast_expr = "(" type ")" expr ;
+// sizeof_expr = "sizeof" "(" (type | expr) ")" ;
+func (p *Parser) parseUnary() Expression {
+	token := p.peek()
+	switch token.GetTokenType() {
+	case lexer.T_PLUS, lexer.T_MINUS:
+		defer p.wrap(p.mark(), CST_UNARY)
+		if op, ok := p.doubledOperator(); ok {
+			return &UnaryExpr{Token: token, Op: op, Operand: p.parseUnary()}
+		}
+		p.advance()
+		return &UnaryExpr{Token: token, Op: token.GetTokenContent(), Operand: p.parseUnary()}
+	case lexer.T_AMPERSAND:
+		defer p.wrap(p.mark(), CST_UNARY)
+		p.advance()
+		if p.match(lexer.T_MUT) {
+			return &UnaryExpr{Token: token, Op: "&mut", Operand: p.parseUnary()}
+		}
+		return &UnaryExpr{Token: token, Op: "&", Operand: p.parseUnary()}
+	case lexer.T_NOT, lexer.T_TILDE, lexer.T_MULTIPLY:
+		defer p.wrap(p.mark(), CST_UNARY)
+		p.advance()
+		return &UnaryExpr{Token: token, Op: token.GetTokenContent(), Operand: p.parseUnary()}
+	case lexer.T_SIZEOF:
+		return p.parseSizeof()
+	case lexer.T_OPENING_PAREN:
+		if p.isCastStart() {
+			defer p.wrap(p.mark(), CST_CAST)
+			p.advance()
+			castType := p.parseType()
+			p.expect(lexer.T_CLOSING_PAREN, "`)` after cast type")
+			return &CastExpr{Token: token, Type: castType, Operand: p.parseUnary()}
+		}
+	}
+	return p.parsePostfix()
+}
+
+// isCastStart decides if a "(" opens a cast rather than a parenthesized expression
+// built in types always cast, a user type name only casts when it is followed
+// by something that can only be an operand (an identifier, literal, ! or ~)
+func (p *Parser) isCastStart() bool {
+	inner := p.peekAt(1)
+	if isBuiltinType(inner) {
+		return true
+	}
+	if inner.GetTokenType() != lexer.T_IDENTIFIER {
+		return false
+	}
+
+	offset := 2
+	for p.peekAt(offset).GetTokenType() == lexer.T_MULTIPLY {
+		offset++
+	}
+	if p.peekAt(offset).GetTokenType() != lexer.T_CLOSING_PAREN {
+		return false
+	}
+	if offset > 2 {
+		// (Point*) can only be a type
+		return true
+	}
+
+	switch p.peekAt(offset + 1).GetTokenType() {
+	case lexer.T_IDENTIFIER, lexer.T_INT_LITERAL, lexer.T_FLOAT_LITERAL, lexer.T_BOOL_LITERAL,
+		lexer.T_STRING_LITERAL, lexer.T_CHAR_LITERAL, lexer.T_RAW_STRING_LITERAL, lexer.T_BYTE_STRING_LITERAL,
+		lexer.T_BYTE_CHAR_LITERAL, lexer.T_LITERAL, lexer.T_NOT, lexer.T_TILDE, lexer.T_SIZEOF:
+		return true
+	}
+	return false
+}
+
+// sizeof_expr = "sizeof" "(" (type | expr) ")" ;
+// a lone identifier is parsed as an expression, semantic analysis decides if it names a type
+func (p *Parser) parseSizeof() Expression {
+	defer p.wrap(p.mark(), CST_SIZEOF)
+	start := p.advance()
+	p.expect(lexer.T_OPENING_PAREN, "`(` after `sizeof`")
+
+	sizeof := &SizeofExpr{Token: start}
+	inner := p.peek()
+	isPointerType := inner.GetTokenType() == lexer.T_IDENTIFIER &&
+		p.peekAt(1).GetTokenType() == lexer.T_MULTIPLY && p.isSizeofPointerType()
+	if isBuiltinType(inner) || isPointerType {
+		sizeof.Type = p.parseType()
+	} else {
+		sizeof.Operand = p.parseExpression()
+	}
+
+	p.expect(lexer.T_CLOSING_PAREN, "`)` after sizeof operand")
+	return sizeof
+}
+
+// isSizeofPointerType checks for sizeof(Point*) as opposed to sizeof(a * b)
+func (p *Parser) isSizeofPointerType() bool {
+	offset := 1
+	for p.peekAt(offset).GetTokenType() == lexer.T_MULTIPLY {
+		offset++
+	}
+	return p.peekAt(offset).GetTokenType() == lexer.T_CLOSING_PAREN
+}
+
+// doubledOperator consumes ++ or -- which the lexer emits as two adjacent tokens
+func (p *Parser) doubledOperator() (string, bool) {
+	first := p.peek()
+	second := p.peekAt(1)
+	if first.GetTokenType() != lexer.T_PLUS && first.GetTokenType() != lexer.T_MINUS {
+		return "", false
+	}
+	if second.GetTokenType() != first.GetTokenType() || !adjacent(first, second) {
+		return "", false
+	}
+	p.advance()
+	p.advance()
+	return first.GetTokenContent() + second.GetTokenContent(), true
+}
+
+// postfix expressions: calls, array access, member access, x++ and x--
+func (p *Parser) parsePostfix() Expression {
+	mark := p.mark()
+	expr := p.parsePrimary()
+	for {
+		token := p.peek()
+		switch token.GetTokenType() {
+		case lexer.T_OPENING_PAREN:
+			p.advance()
+			call := &CallExpr{Token: token, Callee: expr}
+			if !p.check(lexer.T_CLOSING_PAREN) {
+				for {
+					call.Arguments = append(call.Arguments, p.parseAssignment())
+					if !p.match(lexer.T_COMMA) {
+						break
+					}
+				}
+			}
+			p.expect(lexer.T_CLOSING_PAREN, "`)` after call arguments")
+			expr = call
+			p.wrap(mark, CST_CALL)
+		case lexer.T_OPENING_BRACKET:
+			p.advance()
+			index := p.parseExpression()
+			p.expect(lexer.T_CLOSING_BRACKET, "`]` after array index")
+			expr = &IndexExpr{Token: token, Array: expr, Index: index}
+			p.wrap(mark, CST_INDEX)
+		case lexer.T_DOT, lexer.T_MEMBER_OPERATOR:
+			p.advance()
+			member := p.expect(lexer.T_IDENTIFIER, "member name")
+			expr = &MemberExpr{
+				Token:  token,
+				Object: expr,
+				Member: member.GetTokenContent(),
+				Arrow:  token.GetTokenType() == lexer.T_MEMBER_OPERATOR,
+			}
+			p.wrap(mark, CST_MEMBER)
+		case lexer.T_PLUS, lexer.T_MINUS:
+			op, ok := p.doubledOperator()
+			if !ok {
+				return expr
+			}
+			expr = &UnaryExpr{Token: token, Op: op, Operand: expr, Postfix: true}
+			p.wrap(mark, CST_UNARY)
+		default:
+			return expr
+		}
+	}
+}
+
+// primary expressions: identifiers, literals, parenthesized and block expressions
+func (p *Parser) parsePrimary() Expression {
+	token := p.peek()
+	switch token.GetTokenType() {
+	case lexer.T_IDENTIFIER:
+		p.advance()
+		return &Identifier{Token: token, Name: token.GetTokenContent()}
+	case lexer.T_INT_LITERAL, lexer.T_FLOAT_LITERAL, lexer.T_BOOL_LITERAL, lexer.T_STRING_LITERAL,
+		lexer.T_CHAR_LITERAL, lexer.T_RAW_STRING_LITERAL, lexer.T_BYTE_STRING_LITERAL, lexer.T_BYTE_CHAR_LITERAL,
+		lexer.T_LITERAL:
+		p.advance()
+		return &Literal{Token: token, Kind: token.GetTokenType(), Value: token.GetTokenContent()}
+	case lexer.T_ERROR:
+		// a number out of range for its suffix keeps its value, the lexer reported it
+		if token.GetValue() != nil {
+			p.advance()
+			return &Literal{Token: token, Kind: token.GetTokenType(), Value: token.GetTokenContent()}
+		}
+	case lexer.T_OPENING_PAREN:
+		defer p.wrap(p.mark(), CST_PAREN)
+		p.advance()
+		expr := p.parseExpression()
+		p.expect(lexer.T_CLOSING_PAREN, "`)` to close parenthesized expression")
+		return expr
+	case lexer.T_OPENING_BRACE:
+		return p.parseBlock()
+	}
+	p.errorExpected("expression")
+	return nil
+}
@@ -0,0 +1,167 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/CFdefense/compiler/src/diagnostics"
+	"github.com/CFdefense/compiler/src/lexer"
+)
+
+// peek returns the current token without consuming it
+// past the end of the stream an empty token is returned
+func (p *Parser) peek() lexer.Token {
+	return p.peekAt(0)
+}
+
+// peekAt looks offset tokens ahead of the current token
+func (p *Parser) peekAt(offset int) lexer.Token {
+	if p.pos+offset < len(p.tokens) {
+		return p.tokens[p.pos+offset]
+	}
+	return lexer.Token{}
+}
+
+// previous returns the last consumed token
+func (p *Parser) previous() lexer.Token {
+	if p.pos > 0 && p.pos <= len(p.tokens) {
+		return p.tokens[p.pos-1]
+	}
+	return lexer.Token{}
+}
+
+// atEnd reports whether every token has been consumed
+func (p *Parser) atEnd() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// advance consumes and returns the current token
+// the token becomes the next leaf of the concrete syntax tree
+func (p *Parser) advance() lexer.Token {
+	token := p.peek()
+	if !p.atEnd() {
+		p.shift(p.pos)
+		p.pos++
+	}
+	return token
+}
+
+// check reports whether the current token has the given type
+func (p *Parser) check(tokenType lexer.TokenType) bool {
+	return !p.atEnd() && p.peek().GetTokenType() == tokenType
+}
+
+// match consumes the current token if it has the given type
+func (p *Parser) match(tokenType lexer.TokenType) bool {
+	if p.check(tokenType) {
+		p.advance()
+		return true
+	}
+	return false
+}
+
+// expect consumes a token of the given type or reports a syntax error
+func (p *Parser) expect(tokenType lexer.TokenType, what string) lexer.Token {
+	if !p.check(tokenType) {
+		p.errorExpected(what)
+	}
+	return p.advance()
+}
+
+// adjacent reports whether two tokens touch with no whitespace between them
+// the lexer splits ++, --, += and friends into single character tokens
+func adjacent(first, second lexer.Token) bool {
+	return first.GetRow() == second.GetRow() &&
+		first.GetCol()+len(first.GetTokenContent()) == second.GetCol()
+}
+
+// isLexerError checks if a token is one the lexer reported an error for
+func isLexerError(token lexer.Token) bool {
+	switch token.GetTokenType() {
+	case lexer.T_ERROR, lexer.T_UNKNOWN:
+		return true
+	}
+	return false
+}
+
+// isTypeStart checks if a token can begin a type
+func isTypeStart(token lexer.Token) bool {
+	switch token.GetTokenType() {
+	case lexer.T_INT_TYPE, lexer.T_BOOL_TYPE, lexer.T_VOID_TYPE, lexer.T_FLOAT_TYPE,
+		lexer.T_I8_TYPE, lexer.T_I16_TYPE, lexer.T_I32_TYPE, lexer.T_I64_TYPE, lexer.T_I128_TYPE,
+		lexer.T_U8_TYPE, lexer.T_U16_TYPE, lexer.T_U32_TYPE, lexer.T_U64_TYPE, lexer.T_U128_TYPE, lexer.T_BIG_TYPE,
+		lexer.T_INT, lexer.T_BOOL_KEYWORD, lexer.T_VOID, lexer.T_IDENTIFIER:
+		return true
+	}
+	return false
+}
+
+// isBuiltinType checks if a token is one of the built in type keywords
+func isBuiltinType(token lexer.Token) bool {
+	return isTypeStart(token) && token.GetTokenType() != lexer.T_IDENTIFIER
+}
+
+// isStringLiteral checks if a token is a string or raw string literal
+// raw strings let asm text hold quotes and backslashes as written
+func isStringLiteral(token lexer.Token) bool {
+	return token.GetTokenType() == lexer.T_STRING_LITERAL || token.GetTokenType() == lexer.T_RAW_STRING_LITERAL
+}
+
+// docText joins doc comments into the text they document
+// the comment markers go along with one space after them, and so does the *
+// that starts each line of a block comment, blank lines around the text are dropped
+func docText(comments []lexer.Token) string {
+	var lines []string
+	for _, comment := range comments {
+		text := comment.GetTokenContent()
+		if comment.GetTokenType() == lexer.T_SINGLE_LINE_DOC_COMMENT {
+			lines = append(lines, strings.TrimPrefix(text[len("///"):], " "))
+			continue
+		}
+		text = strings.TrimSuffix(text[len("/**"):], "*/")
+		blockLines := strings.Split(text, "\n")
+		for i, line := range blockLines {
+			if i > 0 {
+				line = strings.TrimPrefix(strings.TrimLeft(line, " \t"), "*")
+			}
+			blockLines[i] = strings.TrimRight(strings.TrimPrefix(line, " "), " \t\r")
+		}
+		for len(blockLines) > 0 && blockLines[0] == "" {
+			blockLines = blockLines[1:]
+		}
+		for len(blockLines) > 0 && blockLines[len(blockLines)-1] == "" {
+			blockLines = blockLines[:len(blockLines)-1]
+		}
+		lines = append(lines, blockLines...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describeToken renders a token for use in error messages
+func describeToken(token lexer.Token) string {
+	if token.GetTokenContent() == "" {
+		return "end of input"
+	}
+	return fmt.Sprintf("`%s`", token.GetTokenContent())
+}
+
+// tokenSpan builds a diagnostic span covering a token
+func tokenSpan(token lexer.Token) diagnostics.Span {
+	return diagnostics.Span{
+		File:   token.GetFile(),
+		Row:    token.GetRow(),
+		Col:    token.GetCol(),
+		Length: utf8.RuneCountInString(token.GetTokenContent()),
+	}
+}
+
+// spanAfter is the single column just past the end of a token
+func spanAfter(token lexer.Token) diagnostics.Span {
+	return diagnostics.Span{
+		File:   token.GetFile(),
+		Row:    token.GetEndRow(),
+		Col:    token.GetEndCol(),
+		Length: 1,
+	}
+}
@@ -0,0 +1,555 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+
+	debugger "github.com/CFdefense/compiler/src/debug"
+	"github.com/CFdefense/compiler/src/diagnostics"
+	"github.com/CFdefense/compiler/src/lexer"
+)
+
+// Parser context object
+type Parser struct {
+	tokens      []lexer.Token
+	pos         int
+	ast         *Program
+	docs        map[int][]lexer.Token // doc comments by the position of the token after them
+	trivia      []tokenTrivia         // trivia of each token, comments included
+	end         []lexer.Trivia        // comments and whitespace of a file that has no tokens
+	endTrivia   []lexer.Trivia        // whitespace the lexer could not give a token, see SetEndTrivia
+	syntax      []SyntaxElement       // concrete syntax tree being built, see wrap
+	cst         *SyntaxNode
+	errors      []string
+	errorPos    int // position of the last syntax error, so a cascade at it is reported once
+	debug       *debugger.Debug
+	diagnostics *diagnostics.Reporter
+}
+
+// raised to unwind out of the descent on a syntax error, caught by the
+// statement or complex object being parsed which then synchronizes
+type bailout struct{}
+
+// Parser object constructor
+func InitializeParser(debug bool) *Parser {
+	return &Parser{
+		tokens:      []lexer.Token{},
+		pos:         0,
+		ast:         &Program{},
+		docs:        make(map[int][]lexer.Token),
+		cst:         &SyntaxNode{Kind: CST_PROGRAM},
+		errors:      []string{},
+		errorPos:    -1,
+		debug:       debugger.InitializeDebugger("PAR", debug),
+		diagnostics: diagnostics.InitializeReporter(debug),
+	}
+}
+
+// Function responsible for all things syntax analysis
+// turns the lexer token stream into an abstract syntax tree, and a concrete
+// syntax tree that keeps all of the source
+// a syntax error does not stop the parser, what failed to parse becomes an
+// error node and parsing goes on from the next synchronization point
+func (p *Parser) SyntaxAnalysis(tokens []lexer.Token) {
+	p.debug.DebugLog(fmt.Sprintf("parser: beginning syntax analysis on %d tokens", len(tokens)), false)
+
+	// comments carry no meaning for the parser, they are only trivia of the
+	// concrete syntax tree. doc comments are also set aside to be attached to
+	// the declaration that follows them
+	p.tokens = make([]lexer.Token, 0, len(tokens))
+	p.trivia = make([]tokenTrivia, 0, len(tokens))
+	p.end = nil
+	p.docs = make(map[int][]lexer.Token)
+	next := 0
+	for _, token := range tokens {
+		switch token.GetTokenType() {
+		case lexer.T_SINGLE_LINE_COMMENT, lexer.T_MULTI_LINE_COMMENT:
+			// only trivia, see collectTrivia
+		case lexer.T_SINGLE_LINE_DOC_COMMENT, lexer.T_MULTI_LINE_DOC_COMMENT:
+			p.docs[next] = append(p.docs[next], token)
+		default:
+			next++
+		}
+	}
+	p.collectTrivia(tokens)
+	p.endTrivia = nil
+	p.syntax = nil
+	p.pos = 0
+	p.errorPos = -1
+
+	p.ast = p.parseProgram()
+	p.reportUnusedDocs()
+	p.debug.DebugLog(fmt.Sprintf("parser: finished, parsed %d declarations with %d syntax errors",
+		len(p.ast.Declarations), len(p.errors)), false)
+}
+
+// Function to reset a parser
+// mostly used in repeated test executions
+func (p *Parser) ResetParser() {
+	p.tokens = []lexer.Token{}
+	p.pos = 0
+	p.ast = &Program{}
+	p.docs = make(map[int][]lexer.Token)
+	p.trivia = nil
+	p.end = nil
+	p.endTrivia = nil
+	p.syntax = nil
+	p.cst = &SyntaxNode{Kind: CST_PROGRAM}
+	p.errors = []string{}
+	p.errorPos = -1
+	p.diagnostics.ResetReporter()
+}
+
+// Function to get the abstract syntax tree
+func (p *Parser) GetAST() *Program {
+	return p.ast
+}
+
+// Function to get any syntax errors from the last analysis
+func (p *Parser) GetErrors() []string {
+	return p.errors
+}
+
+// Function to get the diagnostics reported while parsing
+func (p *Parser) GetReporter() *diagnostics.Reporter {
+	return p.diagnostics
+}
+
+// Function to set the whitespace of a file with no tokens, see lexer GetEndTrivia
+// it goes into the concrete syntax tree of the next analysis only
+func (p *Parser) SetEndTrivia(trivia []lexer.Trivia) {
+	p.endTrivia = trivia
+}
+
+// Function to share a diagnostics reporter with the other compiler phases
+func (p *Parser) SetReporter(reporter *diagnostics.Reporter) {
+	p.diagnostics = reporter
+}
+
+// program = complex_object_list ;
+func (p *Parser) parseProgram() *Program {
+	program := &Program{}
+	p.ast = program
+	for !p.atEnd() {
+		program.Declarations = append(program.Declarations, p.parseRecoveringObject())
+	}
+	if p.end != nil {
+		p.syntax = append(p.syntax, &SyntaxToken{Leading: p.end})
+	}
+	p.cst = &SyntaxNode{Kind: CST_PROGRAM, Children: p.syntax}
+	return program
+}
+
+// parseRecoveringObject parses a complex object, one that fails to parse is
+// skipped up to the next synchronization point and left as an error node
+func (p *Parser) parseRecoveringObject() (decl Declaration) {
+	start, startToken, mark := p.pos, p.peek(), p.mark()
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(bailout); !ok {
+				panic(r)
+			}
+			p.synchronize(start)
+			if p.pos == start {
+				// a stray } at the top level, nothing else can fail without being consumed
+				p.skip()
+			}
+			p.wrap(mark, CST_ERROR)
+			decl = &ErrorDecl{Token: startToken}
+		}
+	}()
+	return p.parseComplexObject()
+}
+
+// complex_object = function | enum | struct | const ;
+// the node of a complex object starts at its decorators
+func (p *Parser) parseComplexObject() Declaration {
+	mark := p.mark()
+	doc := p.takeDoc()
+	decorators := p.parseDecorators()
+
+	switch p.peek().GetTokenType() {
+	case lexer.T_ENUM:
+		defer p.wrap(mark, CST_ENUM)
+		enum := p.parseEnum(decorators)
+		enum.Doc = doc
+		return enum
+	case lexer.T_STRUCT:
+		defer p.wrap(mark, CST_STRUCT)
+		structDecl := p.parseStruct(decorators)
+		structDecl.Doc = doc
+		return structDecl
+	case lexer.T_CONST:
+		defer p.wrap(mark, CST_CONST)
+		constDecl := p.parseConst(decorators)
+		constDecl.Doc = doc
+		return constDecl
+	default:
+		defer p.wrap(mark, CST_FUNCTION)
+		function := p.parseFunction(decorators)
+		function.Doc = doc
+		return function
+	}
+}
+
+// decorator = "@" id | ε ;
+func (p *Parser) parseDecorators() []*Decorator {
+	var decorators []*Decorator
+	for p.check(lexer.T_AT) {
+		mark := p.mark()
+		at := p.advance()
+		name := p.expect(lexer.T_IDENTIFIER, "decorator name after `@`")
+		decorators = append(decorators, &Decorator{Token: at, Name: name.GetTokenContent()})
+		p.wrap(mark, CST_DECORATOR)
+	}
+	return decorators
+}
+
+// function = decorator type id "(" param_list ")" block ;
+func (p *Parser) parseFunction(decorators []*Decorator) *FunctionDecl {
+	start := p.peek()
+	returnType := p.parseType()
+	name := p.expect(lexer.T_IDENTIFIER, "function name")
+	p.expect(lexer.T_OPENING_PAREN, "`(` after function name")
+	params := p.parseParamList()
+	p.expect(lexer.T_CLOSING_PAREN, "`)` after parameters")
+	body := p.parseBlock()
+
+	return &FunctionDecl{
+		Token:      start,
+		Decorators: decorators,
+		ReturnType: returnType,
+		Name:       name.GetTokenContent(),
+		Params:     params,
+		Body:       body,
+	}
+}
+
+// enum = "enum" id "{" variant ("," variant)* ","? "}" ;
+// variant = id ( "(" type ("," type)* ")" )? ( "=" expr )? ;
+func (p *Parser) parseEnum(decorators []*Decorator) *EnumDecl {
+	start := p.advance()
+	name := p.expect(lexer.T_IDENTIFIER, "enum name")
+	p.expect(lexer.T_OPENING_BRACE, "`{` after enum name")
+
+	enum := &EnumDecl{Token: start, Decorators: decorators, Name: name.GetTokenContent()}
+	for !p.check(lexer.T_CLOSING_BRACE) && !p.atEnd() {
+		mark := p.mark()
+		doc := p.takeDoc()
+		variantName := p.expect(lexer.T_IDENTIFIER, "enum variant name")
+		variant := &EnumVariant{Token: variantName, Doc: doc, Name: variantName.GetTokenContent()}
+		if p.match(lexer.T_OPENING_PAREN) {
+			for !p.check(lexer.T_CLOSING_PAREN) {
+				variant.Fields = append(variant.Fields, p.parseType())
+				if !p.match(lexer.T_COMMA) {
+					break
+				}
+			}
+			p.expect(lexer.T_CLOSING_PAREN, "`)` after variant fields")
+		}
+		if p.match(lexer.T_ASSIGN) {
+			variant.Value = p.parseAssignment()
+		}
+		p.wrap(mark, CST_VARIANT)
+		enum.Variants = append(enum.Variants, variant)
+		if !p.match(lexer.T_COMMA) {
+			break
+		}
+	}
+	p.expect(lexer.T_CLOSING_BRACE, "`}` after enum variants")
+	return enum
+}
+
+// struct = "struct" id "{" var_decl* "}" ;
+func (p *Parser) parseStruct(decorators []*Decorator) *StructDecl {
+	start := p.advance()
+	name := p.expect(lexer.T_IDENTIFIER, "struct name")
+	p.expect(lexer.T_OPENING_BRACE, "`{` after struct name")
+
+	structDecl := &StructDecl{Token: start, Decorators: decorators, Name: name.GetTokenContent()}
+	for !p.check(lexer.T_CLOSING_BRACE) && !p.atEnd() {
+		doc := p.takeDoc()
+		field := p.parseVarDecl()
+		field.Doc = doc
+		structDecl.Fields = append(structDecl.Fields, field)
+	}
+	p.expect(lexer.T_CLOSING_BRACE, "`}` after struct fields")
+	return structDecl
+}
+
+// const = "const" type id "=" expr ";" ;
+func (p *Parser) parseConst(decorators []*Decorator) *ConstDecl {
+	start := p.advance()
+	constType := p.parseType()
+	name := p.expect(lexer.T_IDENTIFIER, "constant name")
+	p.expect(lexer.T_ASSIGN, "`=` after constant name")
+	value := p.parseExpression()
+	p.expect(lexer.T_SEMICOLON, "`;` after constant")
+
+	return &ConstDecl{
+		Token:      start,
+		Decorators: decorators,
+		Type:       constType,
+		Name:       name.GetTokenContent(),
+		Value:      value,
+	}
+}
+
+// type = "int" | "bool" | "void" | id , followed by any number of "*"
+// only used where a type is the only thing that may appear
+func (p *Parser) parseType() *TypeNode {
+	defer p.wrap(p.mark(), CST_TYPE)
+	if !isTypeStart(p.peek()) {
+		p.errorExpected("type")
+	}
+	token := p.advance()
+	typeNode := &TypeNode{Token: token, Name: token.GetTokenContent()}
+	for p.check(lexer.T_MULTIPLY) && p.isTypePointer() {
+		p.advance()
+		typeNode.Pointers++
+	}
+	return typeNode
+}
+
+// a "*" after a type is part of the type rather than the declarator
+// only when no declarator name follows it, as in (void*)ptr or (*fp)(int*, bool)
+func (p *Parser) isTypePointer() bool {
+	offset := 0
+	for p.peekAt(offset).GetTokenType() == lexer.T_MULTIPLY {
+		offset++
+	}
+	next := p.peekAt(offset).GetTokenType()
+	return next == lexer.T_CLOSING_PAREN || next == lexer.T_COMMA
+}
+
+// param_list = param "," param_list | param | ε ;
+func (p *Parser) parseParamList() []*Param {
+	defer p.wrap(p.mark(), CST_PARAMS)
+	var params []*Param
+	if p.check(lexer.T_CLOSING_PAREN) {
+		return params
+	}
+	for {
+		params = append(params, p.parseParam())
+		if !p.match(lexer.T_COMMA) {
+			return params
+		}
+	}
+}
+
+// param = type mut_spec declarator ;
+func (p *Parser) parseParam() *Param {
+	defer p.wrap(p.mark(), CST_PARAM)
+	start := p.peek()
+	mutable := p.match(lexer.T_MUT)
+	paramType := p.parseType()
+	if p.match(lexer.T_MUT) {
+		mutable = true
+	}
+
+	// function pointer parameter lists may leave out the names: int (*fp)(int, bool)
+	if p.check(lexer.T_COMMA) || p.check(lexer.T_CLOSING_PAREN) {
+		return &Param{Token: start, Type: paramType, Mutable: mutable, Declarator: &Declarator{Token: start}}
+	}
+	return &Param{
+		Token:      start,
+		Type:       paramType,
+		Mutable:    mutable,
+		Declarator: p.parseDeclarator(),
+	}
+}
+
+// declarator = id | "*" declarator | declarator "[" expr? "]" |
+//
+//	"(" "*" id ")" "(" param_list ")" ;
+func (p *Parser) parseDeclarator() *Declarator {
+	defer p.wrap(p.mark(), CST_DECLARATOR)
+	start := p.peek()
+	declarator := &Declarator{Token: start}
+	for p.match(lexer.T_MULTIPLY) {
+		declarator.Pointers++
+	}
+
+	if p.check(lexer.T_OPENING_PAREN) {
+		// function pointer
+		p.advance()
+		p.expect(lexer.T_MULTIPLY, "`*` in function pointer declarator")
+		name := p.expect(lexer.T_IDENTIFIER, "function pointer name")
+		p.expect(lexer.T_CLOSING_PAREN, "`)` after function pointer name")
+		p.expect(lexer.T_OPENING_PAREN, "`(` before function pointer parameters")
+		declarator.FuncParams = p.parseParamList()
+		p.expect(lexer.T_CLOSING_PAREN, "`)` after function pointer parameters")
+		declarator.Name = name.GetTokenContent()
+		declarator.IsFuncPointer = true
+	} else {
+		name := p.expect(lexer.T_IDENTIFIER, "identifier")
+		declarator.Name = name.GetTokenContent()
+	}
+
+	for p.match(lexer.T_OPENING_BRACKET) {
+		if p.match(lexer.T_CLOSING_BRACKET) {
+			declarator.ArrayDims = append(declarator.ArrayDims, nil)
+			continue
+		}
+		declarator.ArrayDims = append(declarator.ArrayDims, p.parseExpression())
+		p.expect(lexer.T_CLOSING_BRACKET, "`]` after array size")
+	}
+	return declarator
+}
+
+// block = "{" statement_list expr? "}" ;
+func (p *Parser) parseBlock() *Block {
+	defer p.wrap(p.mark(), CST_BLOCK)
+	start := p.expect(lexer.T_OPENING_BRACE, "`{`")
+	block := &Block{Token: start}
+
+	for !p.check(lexer.T_CLOSING_BRACE) && !p.atEnd() && !p.atComplexObject() {
+		stmt, result := p.parseRecoveringStatement()
+		if result != nil {
+			block.Result = result
+			break
+		}
+		block.Statements = append(block.Statements, stmt)
+	}
+
+	// a block cut off by the end of input or the next complex object is kept as it is
+	if p.atEnd() || p.atComplexObject() {
+		p.reportExpected("`}` to close block")
+		return block
+	}
+	p.expect(lexer.T_CLOSING_BRACE, "`}` to close block")
+	return block
+}
+
+// parseRecoveringStatement parses a statement of a block, one that fails to parse is
+// skipped up to the next synchronization point and left as an error node
+func (p *Parser) parseRecoveringStatement() (stmt Statement, result Expression) {
+	start, startToken, mark := p.pos, p.peek(), p.mark()
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(bailout); !ok {
+				panic(r)
+			}
+			p.synchronize(start)
+			p.wrap(mark, CST_ERROR)
+			stmt, result = &ErrorStatement{Token: startToken}, nil
+		}
+	}()
+	return p.parseStatement()
+}
+
+// synchronize skips what is left of a statement or complex object after a syntax error
+// braces it opened before the error are closed first, then it stops after a ; or the }
+// that ends it, or before a } closing the block around it or the start of a complex object
+func (p *Parser) synchronize(start int) {
+	depth := 0
+	for _, token := range p.tokens[start:p.pos] {
+		switch token.GetTokenType() {
+		case lexer.T_OPENING_BRACE:
+			depth++
+		case lexer.T_CLOSING_BRACE:
+			depth = max(depth-1, 0)
+		}
+	}
+
+	for !p.atEnd() && !p.atComplexObject() {
+		switch p.peek().GetTokenType() {
+		case lexer.T_SEMICOLON:
+			if depth == 0 {
+				p.skip()
+				return
+			}
+		case lexer.T_OPENING_BRACE:
+			depth++
+		case lexer.T_CLOSING_BRACE:
+			if depth == 0 {
+				return
+			}
+			depth--
+			if depth == 0 {
+				p.skip()
+				return
+			}
+		}
+		p.skip()
+	}
+}
+
+// atComplexObject checks if the current token can only start a complex object
+// functions start with a type, which statements do as well, so they are not counted
+func (p *Parser) atComplexObject() bool {
+	switch p.peek().GetTokenType() {
+	case lexer.T_STRUCT, lexer.T_ENUM, lexer.T_CONST, lexer.T_AT:
+		return !p.atEnd()
+	}
+	return false
+}
+
+// skip drops the current token while synchronizing, along with the doc comments before it
+func (p *Parser) skip() {
+	delete(p.docs, p.pos)
+	p.advance()
+}
+
+// takeDoc removes the doc comments in front of the current token and returns their text
+func (p *Parser) takeDoc() string {
+	comments := p.docs[p.pos]
+	delete(p.docs, p.pos)
+	return docText(comments)
+}
+
+// reportUnusedDocs warns about every doc comment no declaration took
+func (p *Parser) reportUnusedDocs() {
+	positions := make([]int, 0, len(p.docs))
+	for pos := range p.docs {
+		positions = append(positions, pos)
+	}
+	sort.Ints(positions)
+	for _, pos := range positions {
+		comment := p.docs[pos][0]
+		span := tokenSpan(comment)
+		span.Length = len("///")
+		p.diagnostics.Warning(diagnostics.W_UNUSED_DOC_COMMENT, span, "unused doc comment").
+			WithLabel("documents nothing").
+			WithNote("doc comments go right before a function, struct, enum, const, field or enum variant")
+	}
+	p.docs = make(map[int][]lexer.Token)
+}
+
+// report records a syntax error at a span without unwinding
+// only the first error at a position is kept, the rest follow from it
+// nothing is recorded at a token the lexer already reported as an error
+func (p *Parser) report(code string, span diagnostics.Span, format string, args ...any) {
+	if p.pos == p.errorPos {
+		return
+	}
+	p.errorPos = p.pos
+	if isLexerError(p.peek()) {
+		return
+	}
+	message := fmt.Sprintf(format, args...)
+	p.errors = append(p.errors, fmt.Sprintf("%d:%d: %s", span.Row, span.Col, message))
+	p.debug.DebugLog(fmt.Sprintf("Syntax error: %s", message), false)
+	p.diagnostics.Error(code, span, "%s", message)
+}
+
+// errorExpected reports that the current token is not what the grammar requires
+// at the end of input the error is reported on the last token, and when the
+// current token starts a later line it is reported just after the last token,
+// where a missing ; belongs
+func (p *Parser) errorExpected(what string) {
+	p.reportExpected(what)
+	panic(bailout{})
+}
+
+// reportExpected is errorExpected without unwinding
+func (p *Parser) reportExpected(what string) {
+	span := tokenSpan(p.peek())
+	switch {
+	case p.atEnd():
+		span = tokenSpan(p.previous())
+	case p.pos > 0 && p.peek().GetRow() > p.previous().GetEndRow():
+		span = spanAfter(p.previous())
+	}
+	p.report(diagnostics.E_EXPECTED_TOKEN, span, "expected %s, found %s", what, describeToken(p.peek()))
+}
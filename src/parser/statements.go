@@ -0,0 +1,351 @@
+package parser
+
+import (
+	"github.com/CFdefense/compiler/src/lexer"
+)
+
+// parseStatement parses a single statement inside of a block
+// when the block ends in an expression without a semicolon
+// that expression is returned as the block result instead
+func (p *Parser) parseStatement() (Statement, Expression) {
+	token := p.peek()
+
+	switch token.GetTokenType() {
+	case lexer.T_OPENING_BRACE:
+		return p.parseBlock(), nil
+	case lexer.T_IF:
+		return p.parseIf(), nil
+	case lexer.T_WHILE:
+		return p.parseWhile(), nil
+	case lexer.T_DO:
+		return p.parseDoWhile(), nil
+	case lexer.T_FOR:
+		return p.parseFor(), nil
+	case lexer.T_MATCH:
+		return p.parseMatch(), nil
+	case lexer.T_ASM:
+		return p.parseAsm(), nil
+	case lexer.T_RETURN:
+		return p.parseReturn(), nil
+	case lexer.T_BREAK:
+		defer p.wrap(p.mark(), CST_BREAK)
+		p.advance()
+		p.expect(lexer.T_SEMICOLON, "`;` after `break`")
+		return &BreakStatement{Token: token}, nil
+	case lexer.T_CONTINUE:
+		defer p.wrap(p.mark(), CST_CONTINUE)
+		p.advance()
+		p.expect(lexer.T_SEMICOLON, "`;` after `continue`")
+		return &ContinueStatement{Token: token}, nil
+	}
+
+	// labeled_statement = id ":" statement ;
+	if token.GetTokenType() == lexer.T_IDENTIFIER && p.peekAt(1).GetTokenType() == lexer.T_COLON {
+		defer p.wrap(p.mark(), CST_LABELED)
+		p.advance()
+		p.advance()
+		body, result := p.parseStatement()
+		if result != nil {
+			body = &ExprStatement{Token: result.GetToken(), Expr: result}
+		}
+		return &LabeledStatement{Token: token, Label: token.GetTokenContent(), Body: body}, nil
+	}
+
+	if p.isVarDeclStart() {
+		return p.parseVarDecl(), nil
+	}
+
+	// expr ";" or the trailing expression of a block
+	mark := p.mark()
+	expr := p.parseExpression()
+	if p.check(lexer.T_CLOSING_BRACE) {
+		return nil, expr
+	}
+	p.expect(lexer.T_SEMICOLON, "`;` after expression")
+	p.wrap(mark, CST_EXPR_STATEMENT)
+	return &ExprStatement{Token: token, Expr: expr}, nil
+}
+
+// isVarDeclStart decides if the upcoming tokens are a variable declaration
+// an identifier only starts a declaration when it is used as a type name
+func (p *Parser) isVarDeclStart() bool {
+	token := p.peek()
+	if token.GetTokenType() == lexer.T_MUT || isBuiltinType(token) {
+		return true
+	}
+	if token.GetTokenType() != lexer.T_IDENTIFIER {
+		return false
+	}
+
+	switch p.peekAt(1).GetTokenType() {
+	case lexer.T_IDENTIFIER, lexer.T_MUT:
+		// Point p; Point mut p;
+		return true
+	case lexer.T_MULTIPLY:
+		// Point *p; as opposed to a * b;
+		offset := 1
+		for p.peekAt(offset).GetTokenType() == lexer.T_MULTIPLY {
+			offset++
+		}
+		if p.peekAt(offset).GetTokenType() != lexer.T_IDENTIFIER {
+			return false
+		}
+		switch p.peekAt(offset + 1).GetTokenType() {
+		case lexer.T_SEMICOLON, lexer.T_ASSIGN, lexer.T_COMMA, lexer.T_OPENING_BRACKET:
+			return true
+		}
+	case lexer.T_OPENING_PAREN:
+		// Point (*fp)(int); as opposed to a call
+		return p.peekAt(2).GetTokenType() == lexer.T_MULTIPLY &&
+			p.peekAt(3).GetTokenType() == lexer.T_IDENTIFIER &&
+			p.peekAt(4).GetTokenType() == lexer.T_CLOSING_PAREN &&
+			p.peekAt(5).GetTokenType() == lexer.T_OPENING_PAREN
+	}
+	return false
+}
+
+// var_decl = type mut_spec declarator ("=" expr)? ("," declarator ("=" expr)?)* ";" ;
+func (p *Parser) parseVarDecl() *VarDecl {
+	defer p.wrap(p.mark(), CST_VAR_DECL)
+	start := p.peek()
+	mutable := p.match(lexer.T_MUT)
+	varType := p.parseType()
+	if p.match(lexer.T_MUT) {
+		mutable = true
+	}
+
+	decl := &VarDecl{Token: start, Type: varType, Mutable: mutable}
+	for {
+		declarator := p.parseDeclarator()
+		if p.match(lexer.T_ASSIGN) {
+			declarator.Init = p.parseAssignment()
+		}
+		decl.Declarators = append(decl.Declarators, declarator)
+		if !p.match(lexer.T_COMMA) {
+			break
+		}
+	}
+	p.expect(lexer.T_SEMICOLON, "`;` after variable declaration")
+	return decl
+}
+
+// if_statement = "if" "(" expr ")" block else_chain? ;
+// else_chain = "else" block | "else" if_statement ;
+func (p *Parser) parseIf() *IfStatement {
+	defer p.wrap(p.mark(), CST_IF)
+	start := p.advance()
+	p.expect(lexer.T_OPENING_PAREN, "`(` after `if`")
+	condition := p.parseExpression()
+	p.expect(lexer.T_CLOSING_PAREN, "`)` after if condition")
+
+	stmt := &IfStatement{Token: start, Condition: condition, Then: p.parseBlock()}
+	if p.match(lexer.T_ELSE) {
+		if p.check(lexer.T_IF) {
+			stmt.Else = p.parseIf()
+		} else {
+			stmt.Else = p.parseBlock()
+		}
+	}
+	return stmt
+}
+
+// while_statement = "while" "(" expr ")" block ;
+func (p *Parser) parseWhile() *WhileStatement {
+	defer p.wrap(p.mark(), CST_WHILE)
+	start := p.advance()
+	p.expect(lexer.T_OPENING_PAREN, "`(` after `while`")
+	condition := p.parseExpression()
+	p.expect(lexer.T_CLOSING_PAREN, "`)` after while condition")
+	return &WhileStatement{Token: start, Condition: condition, Body: p.parseBlock()}
+}
+
+// do_while_statement = "do" block "while" "(" expr ")" ";" ;
+func (p *Parser) parseDoWhile() *DoWhileStatement {
+	defer p.wrap(p.mark(), CST_DO_WHILE)
+	start := p.advance()
+	body := p.parseBlock()
+	p.expect(lexer.T_WHILE, "`while` after do block")
+	p.expect(lexer.T_OPENING_PAREN, "`(` after `while`")
+	condition := p.parseExpression()
+	p.expect(lexer.T_CLOSING_PAREN, "`)` after while condition")
+	p.expect(lexer.T_SEMICOLON, "`;` after do-while")
+	return &DoWhileStatement{Token: start, Body: body, Condition: condition}
+}
+
+// for_statement = "for" "(" for_init? ";" expr? ";" for_update? ")" block ;
+// for_init = var_decl | assignment_statement ;
+func (p *Parser) parseFor() *ForStatement {
+	defer p.wrap(p.mark(), CST_FOR)
+	start := p.advance()
+	p.expect(lexer.T_OPENING_PAREN, "`(` after `for`")
+	stmt := &ForStatement{Token: start}
+
+	// both forms of for_init consume their own semicolon
+	if !p.match(lexer.T_SEMICOLON) {
+		if p.isVarDeclStart() {
+			stmt.Init = p.parseVarDecl()
+		} else {
+			initToken, mark := p.peek(), p.mark()
+			stmt.Init = &ExprStatement{Token: initToken, Expr: p.parseExpression()}
+			p.expect(lexer.T_SEMICOLON, "`;` after for initializer")
+			p.wrap(mark, CST_EXPR_STATEMENT)
+		}
+	}
+
+	if !p.check(lexer.T_SEMICOLON) {
+		stmt.Condition = p.parseExpression()
+	}
+	p.expect(lexer.T_SEMICOLON, "`;` after for condition")
+
+	if !p.check(lexer.T_CLOSING_PAREN) {
+		stmt.Update = p.parseExpression()
+	}
+	p.expect(lexer.T_CLOSING_PAREN, "`)` after for clauses")
+
+	stmt.Body = p.parseBlock()
+	return stmt
+}
+
+// match_statement = "match" condition match_block ;
+// match_block = "{" arm* "}" ;
+// arm = (expr | "_") "=>" (block | expr) ","? ;
+func (p *Parser) parseMatch() *MatchStatement {
+	defer p.wrap(p.mark(), CST_MATCH)
+	start := p.advance()
+	stmt := &MatchStatement{Token: start}
+	if !p.check(lexer.T_OPENING_BRACE) {
+		stmt.Subject = p.parseExpression()
+	}
+	p.expect(lexer.T_OPENING_BRACE, "`{` to open match arms")
+
+	for !p.check(lexer.T_CLOSING_BRACE) && !p.atEnd() {
+		mark := p.mark()
+		arm := &MatchArm{Token: p.peek()}
+		if !p.match(lexer.T_UNDERSCORE) {
+			arm.Pattern = p.parseAssignment()
+		}
+		p.expect(lexer.T_ARROW, "`=>` after match pattern")
+		if p.check(lexer.T_OPENING_BRACE) {
+			arm.Body = p.parseBlock()
+		} else {
+			arm.Body = p.parseAssignment()
+		}
+		stmt.Arms = append(stmt.Arms, arm)
+		p.match(lexer.T_COMMA)
+		p.wrap(mark, CST_ARM)
+	}
+
+	p.expect(lexer.T_CLOSING_BRACE, "`}` to close match arms")
+	return stmt
+}
+
+// asm_statement = "asm" "(" asm_string asm_operands? ")" ";" | "asm" "{" (asm_string asm_operands | asm_block) "}" ;
+// asm_block = asm_line* ;
+// asm_line = asm_string ";" | raw instruction tokens up to the end of the line ;
+func (p *Parser) parseAsm() *AsmStatement {
+	defer p.wrap(p.mark(), CST_ASM)
+	start := p.advance()
+	stmt := &AsmStatement{Token: start}
+
+	if p.match(lexer.T_OPENING_PAREN) {
+		if !isStringLiteral(p.peek()) {
+			p.errorExpected("string literal in `asm(...)`")
+		}
+		text := p.advance()
+		stmt.Lines = append(stmt.Lines, &AsmLine{Token: text, Text: text.GetTokenContent()})
+		if p.check(lexer.T_COLON) {
+			p.parseAsmOperands(stmt)
+		}
+		p.expect(lexer.T_CLOSING_PAREN, "`)` after asm string")
+		p.expect(lexer.T_SEMICOLON, "`;` after asm statement")
+		return stmt
+	}
+
+	p.expect(lexer.T_OPENING_BRACE, "`(` or `{` after `asm`")
+	for !p.check(lexer.T_CLOSING_BRACE) && !p.atEnd() {
+		if isStringLiteral(p.peek()) {
+			mark := p.mark()
+			text := p.advance()
+			stmt.Lines = append(stmt.Lines, &AsmLine{Token: text, Text: text.GetTokenContent()})
+			if len(stmt.Lines) == 1 && p.check(lexer.T_COLON) {
+				// the template of extended asm is the only line of its block
+				p.parseAsmOperands(stmt)
+				p.match(lexer.T_SEMICOLON)
+				break
+			}
+			p.expect(lexer.T_SEMICOLON, "`;` after asm line")
+			p.wrap(mark, CST_ASM_LINE)
+			continue
+		}
+		if p.match(lexer.T_SEMICOLON) {
+			continue
+		}
+
+		// raw instruction line, runs until a newline, semicolon or the closing brace
+		first, mark := p.peek(), p.mark()
+		line := &AsmLine{Token: first}
+		for !p.atEnd() && !p.check(lexer.T_CLOSING_BRACE) && !p.check(lexer.T_SEMICOLON) &&
+			p.peek().GetRow() == first.GetRow() {
+			line.Tokens = append(line.Tokens, p.advance())
+		}
+		p.wrap(mark, CST_ASM_LINE)
+		stmt.Lines = append(stmt.Lines, line)
+	}
+
+	p.expect(lexer.T_CLOSING_BRACE, "`}` to close asm block")
+	return stmt
+}
+
+// asm_operands = ":" asm_bindings? (":" asm_bindings? (":" asm_clobbers?)?)? ;
+// asm_bindings = asm_binding ("," asm_binding)* ;
+// asm_binding = string_literal "(" expr ")" ;
+// asm_clobbers = string_literal ("," string_literal)* ;
+func (p *Parser) parseAsmOperands(stmt *AsmStatement) {
+	stmt.Extended = true
+	p.advance()
+	stmt.Outputs = p.parseAsmBindings()
+	if !p.match(lexer.T_COLON) {
+		return
+	}
+	stmt.Inputs = p.parseAsmBindings()
+	if !p.match(lexer.T_COLON) {
+		return
+	}
+	for p.check(lexer.T_STRING_LITERAL) {
+		stmt.Clobbers = append(stmt.Clobbers, p.advance())
+		if !p.match(lexer.T_COMMA) {
+			break
+		}
+	}
+}
+
+// parseAsmBindings reads the outputs or inputs of extended asm, which may be none
+func (p *Parser) parseAsmBindings() []*AsmOperand {
+	var operands []*AsmOperand
+	for p.check(lexer.T_STRING_LITERAL) {
+		mark := p.mark()
+		constraint := p.advance()
+		operand := &AsmOperand{Token: constraint, Constraint: constraint.GetValue().GetString()}
+		p.expect(lexer.T_OPENING_PAREN, "`(` before the expression an asm operand binds")
+		operand.Expr = p.parseExpression()
+		p.expect(lexer.T_CLOSING_PAREN, "`)` after the expression an asm operand binds")
+		p.wrap(mark, CST_ASM_OPERAND)
+		operands = append(operands, operand)
+		if !p.match(lexer.T_COMMA) {
+			break
+		}
+	}
+	return operands
+}
+
+// jump_statement = "return" expr? ";" ;
+func (p *Parser) parseReturn() *ReturnStatement {
+	defer p.wrap(p.mark(), CST_RETURN)
+	start := p.advance()
+	stmt := &ReturnStatement{Token: start}
+	if !p.check(lexer.T_SEMICOLON) {
+		stmt.Value = p.parseExpression()
+	}
+	p.expect(lexer.T_SEMICOLON, "`;` after return")
+	return stmt
+}
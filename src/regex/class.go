@@ -0,0 +1,158 @@
+package regex
+
+import (
+	"slices"
+	"unicode/utf8"
+)
+
+// runeRange is an inclusive range of runes
+type runeRange struct {
+	lo, hi rune
+}
+
+// class is a set of runes as a list of ranges
+// a normalized class is sorted with no ranges overlapping or touching
+type class []runeRange
+
+// byteRange is an inclusive range of bytes
+type byteRange struct {
+	lo, hi byte
+}
+
+// first and last surrogate, they have no UTF-8 encoding
+const (
+	surrogateMin = 0xD800
+	surrogateMax = 0xDFFF
+)
+
+// singleRune builds the class of one rune
+func singleRune(r rune) class {
+	return class{{r, r}}
+}
+
+// anyCharExceptNewline builds the class . matches
+func anyCharExceptNewline() class {
+	return class{{0, '\n' - 1}, {'\n' + 1, utf8.MaxRune}}
+}
+
+// perlClass builds the class of \d, \w or \s, or the negation for \D, \W or \S
+func perlClass(c byte) class {
+	var ranges class
+	switch c {
+	case 'd', 'D':
+		ranges = class{{'0', '9'}}
+	case 'w', 'W':
+		ranges = class{{'0', '9'}, {'A', 'Z'}, {'_', '_'}, {'a', 'z'}}
+	case 's', 'S':
+		ranges = class{{'\t', '\n'}, {'\f', '\r'}, {' ', ' '}}
+	}
+	if c == 'D' || c == 'W' || c == 'S' {
+		return ranges.negate()
+	}
+	return ranges
+}
+
+// normalize sorts a class and merges ranges that overlap or touch
+func (c class) normalize() class {
+	sorted := slices.Clone(c)
+	slices.SortFunc(sorted, func(a, b runeRange) int { return int(a.lo - b.lo) })
+
+	var merged class
+	for _, r := range sorted {
+		if last := len(merged) - 1; last >= 0 && r.lo <= merged[last].hi+1 {
+			merged[last].hi = max(merged[last].hi, r.hi)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// negate returns every rune not in a normalized class
+func (c class) negate() class {
+	var negated class
+	next := rune(0)
+	for _, r := range c {
+		if r.lo > next {
+			negated = append(negated, runeRange{next, r.lo - 1})
+		}
+		next = r.hi + 1
+	}
+	if next <= utf8.MaxRune {
+		negated = append(negated, runeRange{next, utf8.MaxRune})
+	}
+	return negated
+}
+
+// utf8Sequences splits a rune range into sequences of byte ranges
+// every rune in the range encodes to exactly one sequence, where each byte
+// of the encoding falls in the byte range at the same position
+// surrogates are left out since they cannot be encoded
+func utf8Sequences(lo, hi rune) [][]byteRange {
+	var sequences [][]byteRange
+	pending := []runeRange{{lo, hi}}
+
+	for len(pending) > 0 {
+		r := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if r.lo > r.hi {
+			continue
+		}
+
+		// cut out the surrogates
+		if r.lo <= surrogateMax && r.hi >= surrogateMin {
+			pending = append(pending, runeRange{surrogateMax + 1, r.hi}, runeRange{r.lo, surrogateMin - 1})
+			continue
+		}
+
+		// split where the encoded length changes
+		if split := splitByLength(r); split != nil {
+			pending = append(pending, split[1], split[0])
+			continue
+		}
+
+		// split until only the last bytes vary independently of each other
+		if split := splitByContinuation(r); split != nil {
+			pending = append(pending, split[1], split[0])
+			continue
+		}
+
+		loBytes := utf8.AppendRune(nil, r.lo)
+		hiBytes := utf8.AppendRune(nil, r.hi)
+		sequence := make([]byteRange, len(loBytes))
+		for i := range loBytes {
+			sequence[i] = byteRange{loBytes[i], hiBytes[i]}
+		}
+		sequences = append(sequences, sequence)
+	}
+
+	return sequences
+}
+
+// splitByLength splits a range that holds runes of more than one encoded length
+func splitByLength(r runeRange) []runeRange {
+	for _, last := range []rune{0x7F, 0x7FF, 0xFFFF} {
+		if r.lo <= last && r.hi > last {
+			return []runeRange{{r.lo, last}, {last + 1, r.hi}}
+		}
+	}
+	return nil
+}
+
+// splitByContinuation splits a range of one encoded length until the
+// sequence of byte ranges from its ends covers exactly the runes in it
+func splitByContinuation(r runeRange) []runeRange {
+	for i := 1; i < utf8.UTFMax; i++ {
+		mask := rune(1)<<(6*i) - 1
+		if r.lo&^mask == r.hi&^mask {
+			continue
+		}
+		if r.lo&mask != 0 {
+			return []runeRange{{r.lo, r.lo | mask}, {(r.lo | mask) + 1, r.hi}}
+		}
+		if r.hi&mask != mask {
+			return []runeRange{{r.lo, r.hi&^mask - 1}, {r.hi &^ mask, r.hi}}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package regex
+
+// machine runs an NFA over a text one byte at a time (a Pike VM)
+// every live thread is tracked in a list ordered by preference, so the
+// preferred match is found in a single pass without backtracking
+type machine struct {
+	nfa *NFA
+}
+
+// threadList is the set of states live at one position, most preferred first
+type threadList struct {
+	states []int
+	seen   []bool
+}
+
+func newMachine(nfa *NFA) *machine {
+	return &machine{nfa: nfa}
+}
+
+// newList creates an empty thread list sized for the NFA
+func (m *machine) newList() *threadList {
+	return &threadList{seen: make([]bool, len(m.nfa.States))}
+}
+
+// clear empties a thread list for reuse
+func (l *threadList) clear() {
+	for _, state := range l.states {
+		l.seen[state] = false
+	}
+	l.states = l.states[:0]
+}
+
+// add puts a state and everything it reaches without consuming input into a list
+// states are added in order of preference, a state already in the list is
+// reached by a more preferred path and is skipped
+func (m *machine) add(list *threadList, state int, text string, pos int) {
+	if list.seen[state] {
+		return
+	}
+	list.seen[state] = true
+	list.states = append(list.states, state)
+
+	s := &m.nfa.States[state]
+	if s.Assert != ASSERT_NONE && !s.Assert.Holds(text, pos) {
+		return
+	}
+	for _, next := range s.Epsilon {
+		m.add(list, next, text, pos)
+	}
+}
+
+// step follows the transitions of a state on the byte at pos into a list
+func (m *machine) step(list *threadList, state int, text string, pos int) {
+	c := text[pos]
+	for _, t := range m.nfa.States[state].Transitions {
+		if t.Lo <= c && c <= t.Hi {
+			m.add(list, t.To, text, pos+1)
+		}
+	}
+}
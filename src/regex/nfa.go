@@ -0,0 +1,180 @@
+package regex
+
+// Assertion is a condition on the text around a position
+type Assertion int
+
+const (
+	ASSERT_NONE              Assertion = iota
+	ASSERT_BEGIN_TEXT                  // ^ and \A, at the start of the text
+	ASSERT_END_TEXT                    // $ and \z, at the end of the text
+	ASSERT_WORD_BOUNDARY               // \b, between a word and a non word character
+	ASSERT_NOT_WORD_BOUNDARY           // \B, anywhere \b does not hold
+)
+
+func (a Assertion) String() string {
+	switch a {
+	case ASSERT_BEGIN_TEXT:
+		return "^"
+	case ASSERT_END_TEXT:
+		return "$"
+	case ASSERT_WORD_BOUNDARY:
+		return `\b`
+	case ASSERT_NOT_WORD_BOUNDARY:
+		return `\B`
+	default:
+		return ""
+	}
+}
+
+// Holds checks if an assertion holds at a byte position of a text
+func (a Assertion) Holds(text string, pos int) bool {
+	switch a {
+	case ASSERT_BEGIN_TEXT:
+		return pos == 0
+	case ASSERT_END_TEXT:
+		return pos == len(text)
+	case ASSERT_WORD_BOUNDARY, ASSERT_NOT_WORD_BOUNDARY:
+		before := pos > 0 && isWordByte(text[pos-1])
+		after := pos < len(text) && isWordByte(text[pos])
+		return (before != after) == (a == ASSERT_WORD_BOUNDARY)
+	}
+	return true
+}
+
+// Transition moves to state To on any byte from Lo to Hi
+type Transition struct {
+	Lo, Hi byte
+	To     int
+}
+
+// State of an NFA
+// Transitions consume one byte, Epsilon moves without consuming anything and
+// is listed in order of preference. when Assert is set the Epsilon moves
+// are only taken where the assertion holds
+type State struct {
+	Transitions []Transition
+	Epsilon     []int
+	Assert      Assertion
+}
+
+// NFA is a Thompson automaton over bytes
+// runes in the pattern are matched by their UTF-8 encoding
+type NFA struct {
+	States []State
+	Start  int
+	Accept int
+}
+
+// compile builds the NFA of a parsed pattern
+func compile(root *node) *NFA {
+	nfa := &NFA{}
+	nfa.Start, nfa.Accept = nfa.build(root)
+	return nfa
+}
+
+// newState adds an empty state and returns its index
+func (nfa *NFA) newState() int {
+	nfa.States = append(nfa.States, State{})
+	return len(nfa.States) - 1
+}
+
+// epsilon adds a move that consumes nothing, after any the state already has
+func (nfa *NFA) epsilon(from, to int) {
+	nfa.States[from].Epsilon = append(nfa.States[from].Epsilon, to)
+}
+
+// build adds the states of a node and returns its start and end states
+// the end state never has moves of its own so it can be linked onward
+func (nfa *NFA) build(n *node) (int, int) {
+	switch n.kind {
+	case nodeClass:
+		start, end := nfa.newState(), nfa.newState()
+		for _, r := range n.class {
+			for _, sequence := range utf8Sequences(r.lo, r.hi) {
+				current := start
+				for i, bytes := range sequence {
+					next := end
+					if i < len(sequence)-1 {
+						next = nfa.newState()
+					}
+					nfa.States[current].Transitions = append(nfa.States[current].Transitions,
+						Transition{Lo: bytes.lo, Hi: bytes.hi, To: next})
+					current = next
+				}
+			}
+		}
+		return start, end
+
+	case nodeConcat:
+		start, end := nfa.build(n.children[0])
+		for _, child := range n.children[1:] {
+			childStart, childEnd := nfa.build(child)
+			nfa.epsilon(end, childStart)
+			end = childEnd
+		}
+		return start, end
+
+	case nodeAlternate:
+		start, end := nfa.newState(), nfa.newState()
+		for _, child := range n.children {
+			childStart, childEnd := nfa.build(child)
+			nfa.epsilon(start, childStart)
+			nfa.epsilon(childEnd, end)
+		}
+		return start, end
+
+	case nodeRepeat:
+		return nfa.buildRepeat(n)
+
+	case nodeAssert:
+		start, end := nfa.newState(), nfa.newState()
+		nfa.States[start].Assert = n.assert
+		nfa.epsilon(start, end)
+		return start, end
+	}
+
+	state := nfa.newState()
+	return state, state
+}
+
+// buildRepeat expands x{m,n} into m copies of x followed by n-m optional
+// copies nested as x?(x?(...)), and x{m,} into m copies followed by x*
+// a lazy repeat prefers to skip a copy rather than match it
+func (nfa *NFA) buildRepeat(n *node) (int, int) {
+	child := n.children[0]
+	start := nfa.newState()
+	current := start
+	for i := 0; i < n.min; i++ {
+		childStart, childEnd := nfa.build(child)
+		nfa.epsilon(current, childStart)
+		current = childEnd
+	}
+
+	end := nfa.newState()
+	choose := func(from, match int) {
+		if n.lazy {
+			nfa.epsilon(from, end)
+			nfa.epsilon(from, match)
+		} else {
+			nfa.epsilon(from, match)
+			nfa.epsilon(from, end)
+		}
+	}
+
+	if n.max == -1 {
+		loop := nfa.newState()
+		nfa.epsilon(current, loop)
+		childStart, childEnd := nfa.build(child)
+		choose(loop, childStart)
+		nfa.epsilon(childEnd, loop)
+		return start, end
+	}
+
+	for i := n.min; i < n.max; i++ {
+		childStart, childEnd := nfa.build(child)
+		choose(current, childStart)
+		current = childEnd
+	}
+	nfa.epsilon(current, end)
+	return start, end
+}
@@ -0,0 +1,116 @@
+// Package regex is the regular expression engine the lexer builds its token
+// automata from. Patterns are parsed into a Thompson NFA over bytes, which can
+// be run directly or handed to the lexer for subset construction.
+//
+// Supported syntax:
+//
+//	x          a literal character, any UTF-8 character may be used
+//	.          any character except newline
+//	[abc]      a class, [a-z] is a range and [^abc] a negated class
+//	\d \w \s   digits, ascii word characters and whitespace, \D \W \S negate them
+//	\n \t \r   newline, tab, carriage return, also \f \v \a
+//	\x7F       a character by hex value, \x{10FFFF} for more than two digits
+//	\.         any escaped ascii punctuation stands for itself
+//	xy         x followed by y
+//	x|y        x or y, x preferred
+//	(x) (?:x)  grouping, groups never capture
+//	x* x+ x?   zero or more, one or more, zero or one
+//	x{m,n}     m to n, x{m} is exactly m and x{m,} at least m, counts go up to 1000
+//	x*? x+?    a trailing ? makes any repetition non greedy
+//	^ \A       start of text
+//	$ \z       end of text
+//	\b \B      ascii word boundary and not a word boundary
+//
+// Escapes not listed, flags and capture groups are rejected with an *Error.
+// Matching follows Go's regexp: among several matches at the same start the
+// one preferred by the pattern wins, rather than the longest.
+package regex
+
+// Regexp is a compiled pattern, it is never modified after Compile
+// so it may be used from several goroutines at once
+type Regexp struct {
+	pattern string
+	nfa     *NFA
+}
+
+// Compile parses a pattern and builds its NFA
+// a pattern outside the supported syntax returns an *Error
+func Compile(pattern string) (*Regexp, error) {
+	root, err := parse(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &Regexp{pattern: pattern, nfa: compile(root)}, nil
+}
+
+// MustCompile is Compile for patterns known to be valid, it panics on an error
+func MustCompile(pattern string) *Regexp {
+	re, err := Compile(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return re
+}
+
+// String returns the pattern the Regexp was compiled from
+func (re *Regexp) String() string {
+	return re.pattern
+}
+
+// Function to get the compiled NFA, it is shared and must not be modified
+func (re *Regexp) GetNFA() *NFA {
+	return re.nfa
+}
+
+// MatchString reports whether s contains a match of the pattern anywhere
+func (re *Regexp) MatchString(s string) bool {
+	m := newMachine(re.nfa)
+	current, next := m.newList(), m.newList()
+
+	for pos := 0; ; pos++ {
+		// a match may start at every position
+		m.add(current, re.nfa.Start, s, pos)
+		next.clear()
+		for _, state := range current.states {
+			if state == re.nfa.Accept {
+				return true
+			}
+			if pos < len(s) {
+				m.step(next, state, s, pos)
+			}
+		}
+		if pos >= len(s) {
+			return false
+		}
+		current, next = next, current
+	}
+}
+
+// FindPrefix finds the match that starts at the beginning of s
+// returns the length of the match, false when there is none
+func (re *Regexp) FindPrefix(s string) (int, bool) {
+	m := newMachine(re.nfa)
+	current, next := m.newList(), m.newList()
+	m.add(current, re.nfa.Start, s, 0)
+
+	matched := -1
+	for pos := 0; len(current.states) > 0; pos++ {
+		next.clear()
+		for _, state := range current.states {
+			if state == re.nfa.Accept {
+				// every later thread is less preferred than this match
+				matched = pos
+				break
+			}
+			if pos < len(s) {
+				m.step(next, state, s, pos)
+			}
+		}
+		if pos >= len(s) {
+			break
+		}
+		current, next = next, current
+	}
+
+	return matched, matched >= 0
+}
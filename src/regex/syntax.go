@@ -0,0 +1,470 @@
+package regex
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// largest count allowed in a {m,n} repetition
+const MAX_REPEAT = 1000
+
+// Error describes why a pattern could not be compiled
+// Offset is the byte offset in the pattern where the problem was found
+type Error struct {
+	Pattern string
+	Offset  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("regex: %s at offset %d in `%s`", e.Message, e.Offset, e.Pattern)
+}
+
+// kinds of node in a parsed pattern
+type nodeKind int
+
+const (
+	nodeEmpty     nodeKind = iota // matches the empty string
+	nodeClass                     // matches one character in class
+	nodeConcat                    // matches every child in order
+	nodeAlternate                 // matches any child, earlier children preferred
+	nodeRepeat                    // matches its child min to max times
+	nodeAssert                    // matches the empty string where assert holds
+)
+
+// node of a parsed pattern
+// single characters are classes with one rune in them
+type node struct {
+	kind     nodeKind
+	class    class
+	children []*node
+	min      int // repeat count, max of -1 means no upper limit
+	max      int
+	lazy     bool // repeat prefers fewer matches
+	assert   Assertion
+}
+
+// parser is a recursive descent parser over a pattern
+//
+//	alternate = concat ("|" concat)* ;
+//	concat    = repeat* ;
+//	repeat    = atom (("*" | "+" | "?" | "{" m ("," n?)? "}") "?"?)? ;
+//	atom      = "(" ("?:")? alternate ")" | class | "." | "^" | "$" | escape | char ;
+type parser struct {
+	pattern string
+	pos     int
+}
+
+// parse turns a pattern into a tree of nodes
+func parse(pattern string) (*node, error) {
+	p := &parser{pattern: pattern}
+	root, err := p.parseAlternate()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.pattern) {
+		// the only thing that stops an alternate early is a close paren
+		return nil, p.errorf(p.pos, "unexpected )")
+	}
+	return root, nil
+}
+
+// errorf builds an Error at an offset in the pattern
+func (p *parser) errorf(offset int, format string, args ...any) *Error {
+	return &Error{Pattern: p.pattern, Offset: offset, Message: fmt.Sprintf(format, args...)}
+}
+
+// more reports whether any of the pattern is left
+func (p *parser) more() bool {
+	return p.pos < len(p.pattern)
+}
+
+// peek returns the next byte of the pattern, 0 at the end
+func (p *parser) peek() byte {
+	if p.more() {
+		return p.pattern[p.pos]
+	}
+	return 0
+}
+
+// alternate = concat ("|" concat)* ;
+func (p *parser) parseAlternate() (*node, error) {
+	var branches []*node
+	for {
+		branch, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, branch)
+		if p.peek() != '|' {
+			break
+		}
+		p.pos++
+	}
+
+	if len(branches) == 1 {
+		return branches[0], nil
+	}
+	return &node{kind: nodeAlternate, children: branches}, nil
+}
+
+// concat = repeat* ;
+func (p *parser) parseConcat() (*node, error) {
+	var items []*node
+	for p.more() && p.peek() != '|' && p.peek() != ')' {
+		item, err := p.parseRepeat()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	switch len(items) {
+	case 0:
+		return &node{kind: nodeEmpty}, nil
+	case 1:
+		return items[0], nil
+	}
+	return &node{kind: nodeConcat, children: items}, nil
+}
+
+// repeat = atom (("*" | "+" | "?" | "{" m ("," n?)? "}") "?"?)? ;
+func (p *parser) parseRepeat() (*node, error) {
+	start := p.pos
+	atom, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	// a quantifier with nothing before it to repeat
+	if atom == nil {
+		return nil, p.errorf(start, "missing argument to repetition operator `%c`", p.pattern[start])
+	}
+
+	repeated := false
+	for p.more() {
+		opStart := p.pos
+		min, max, ok := p.parseQuantifier()
+		if !ok {
+			break
+		}
+		if repeated {
+			return nil, p.errorf(opStart, "invalid nested repetition operator `%s`", p.pattern[opStart:p.pos])
+		}
+		if min > MAX_REPEAT || max > MAX_REPEAT || (max != -1 && max < min) {
+			return nil, p.errorf(opStart, "invalid repeat count `%s`", p.pattern[opStart:p.pos])
+		}
+
+		lazy := false
+		if p.peek() == '?' {
+			lazy = true
+			p.pos++
+		}
+		atom = &node{kind: nodeRepeat, children: []*node{atom}, min: min, max: max, lazy: lazy}
+		repeated = true
+	}
+	return atom, nil
+}
+
+// parseQuantifier consumes a quantifier if one is next
+// a "{" that does not start a valid {m,n} is left alone and read as a literal
+func (p *parser) parseQuantifier() (int, int, bool) {
+	switch p.peek() {
+	case '*':
+		p.pos++
+		return 0, -1, true
+	case '+':
+		p.pos++
+		return 1, -1, true
+	case '?':
+		p.pos++
+		return 0, 1, true
+	case '{':
+		return p.parseCount()
+	}
+	return 0, 0, false
+}
+
+// parseCount reads {m}, {m,} or {m,n}
+func (p *parser) parseCount() (int, int, bool) {
+	i := p.pos + 1
+	min, i, ok := parseDecimal(p.pattern, i)
+	if !ok {
+		return 0, 0, false
+	}
+	max := min
+	if i < len(p.pattern) && p.pattern[i] == ',' {
+		i++
+		max = -1
+		if i < len(p.pattern) && p.pattern[i] != '}' {
+			if max, i, ok = parseDecimal(p.pattern, i); !ok {
+				return 0, 0, false
+			}
+		}
+	}
+	if i >= len(p.pattern) || p.pattern[i] != '}' {
+		return 0, 0, false
+	}
+	p.pos = i + 1
+	return min, max, true
+}
+
+// parseDecimal reads the digits at i, counts past MAX_REPEAT are clamped
+// so an oversized count is still reported as one
+func parseDecimal(s string, i int) (int, int, bool) {
+	start := i
+	value := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		value = min(value*10+int(s[i]-'0'), MAX_REPEAT+1)
+		i++
+	}
+	return value, i, i > start
+}
+
+// atom = "(" ("?:")? alternate ")" | class | "." | "^" | "$" | escape | char ;
+// returns nil for a quantifier with nothing before it
+func (p *parser) parseAtom() (*node, error) {
+	switch c := p.peek(); c {
+	case '(':
+		return p.parseGroup()
+	case '[':
+		return p.parseClass()
+	case '.':
+		p.pos++
+		return &node{kind: nodeClass, class: anyCharExceptNewline()}, nil
+	case '^':
+		p.pos++
+		return &node{kind: nodeAssert, assert: ASSERT_BEGIN_TEXT}, nil
+	case '$':
+		p.pos++
+		return &node{kind: nodeAssert, assert: ASSERT_END_TEXT}, nil
+	case '\\':
+		return p.parseEscape()
+	case '*', '+', '?':
+		return nil, nil
+	case '{':
+		if _, _, ok := (&parser{pattern: p.pattern, pos: p.pos}).parseCount(); ok {
+			return nil, nil
+		}
+	}
+
+	r, size := utf8.DecodeRuneInString(p.pattern[p.pos:])
+	if r == utf8.RuneError && size == 1 {
+		return nil, p.errorf(p.pos, "invalid UTF-8")
+	}
+	p.pos += size
+	return &node{kind: nodeClass, class: singleRune(r)}, nil
+}
+
+// group = "(" ("?:")? alternate ")" ;
+// groups do not capture so (?:x) is the same as (x)
+func (p *parser) parseGroup() (*node, error) {
+	open := p.pos
+	p.pos++
+	if strings.HasPrefix(p.pattern[p.pos:], "?:") {
+		p.pos += 2
+	} else if p.peek() == '?' {
+		return nil, p.errorf(open, "unsupported group flags")
+	}
+
+	inner, err := p.parseAlternate()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != ')' {
+		return nil, p.errorf(open, "missing closing )")
+	}
+	p.pos++
+	return inner, nil
+}
+
+// escape = "\" ( assertion | perl class | control | "x" hex | punctuation ) ;
+func (p *parser) parseEscape() (*node, error) {
+	start := p.pos
+	if p.pos+1 >= len(p.pattern) {
+		return nil, p.errorf(start, "trailing backslash at end of expression")
+	}
+
+	switch p.pattern[p.pos+1] {
+	case 'b':
+		p.pos += 2
+		return &node{kind: nodeAssert, assert: ASSERT_WORD_BOUNDARY}, nil
+	case 'B':
+		p.pos += 2
+		return &node{kind: nodeAssert, assert: ASSERT_NOT_WORD_BOUNDARY}, nil
+	case 'A':
+		p.pos += 2
+		return &node{kind: nodeAssert, assert: ASSERT_BEGIN_TEXT}, nil
+	case 'z':
+		p.pos += 2
+		return &node{kind: nodeAssert, assert: ASSERT_END_TEXT}, nil
+	}
+
+	class, err := p.parseClassEscape()
+	if err != nil {
+		return nil, err
+	}
+	return &node{kind: nodeClass, class: class}, nil
+}
+
+// parseClassEscape reads an escape that stands for characters
+// these are the escapes allowed both inside and outside of a class
+func (p *parser) parseClassEscape() (class, error) {
+	start := p.pos
+	if p.pos+1 >= len(p.pattern) {
+		return nil, p.errorf(start, "trailing backslash at end of expression")
+	}
+	c := p.pattern[p.pos+1]
+	p.pos += 2
+
+	switch c {
+	case 'd', 'D', 'w', 'W', 's', 'S':
+		return perlClass(c), nil
+	case 'n':
+		return singleRune('\n'), nil
+	case 't':
+		return singleRune('\t'), nil
+	case 'r':
+		return singleRune('\r'), nil
+	case 'f':
+		return singleRune('\f'), nil
+	case 'v':
+		return singleRune('\v'), nil
+	case 'a':
+		return singleRune('\a'), nil
+	case 'x':
+		r, err := p.parseHex(start)
+		if err != nil {
+			return nil, err
+		}
+		return singleRune(r), nil
+	}
+
+	// any ascii punctuation can be escaped to stand for itself
+	if c < utf8.RuneSelf && !isWordByte(c) && c > ' ' {
+		return singleRune(rune(c)), nil
+	}
+	return nil, p.errorf(start, "invalid escape sequence `%s`", p.pattern[start:min(start+2, len(p.pattern))])
+}
+
+// parseHex reads the digits of \xHH or \x{H...}
+func (p *parser) parseHex(start int) (rune, error) {
+	digits := ""
+	if p.peek() == '{' {
+		end := strings.IndexByte(p.pattern[p.pos:], '}')
+		if end < 0 {
+			return 0, p.errorf(start, "missing closing } in hex escape")
+		}
+		digits = p.pattern[p.pos+1 : p.pos+end]
+		p.pos += end + 1
+	} else if p.pos+2 <= len(p.pattern) {
+		digits = p.pattern[p.pos : p.pos+2]
+		p.pos += 2
+	}
+
+	var value rune
+	for i := 0; i < len(digits); i++ {
+		digit := hexValue(digits[i])
+		if digit < 0 || value > utf8.MaxRune {
+			return 0, p.errorf(start, "invalid hex escape `%s`", p.pattern[start:p.pos])
+		}
+		value = value*16 + rune(digit)
+	}
+	if digits == "" || value > utf8.MaxRune {
+		return 0, p.errorf(start, "invalid hex escape `%s`", p.pattern[start:p.pos])
+	}
+	return value, nil
+}
+
+// class = "[" "^"? "]"? item* "]" ;
+// item = (escape | char) ("-" (escape | char))? ;
+func (p *parser) parseClass() (*node, error) {
+	open := p.pos
+	p.pos++
+	negated := false
+	if p.peek() == '^' {
+		negated = true
+		p.pos++
+	}
+
+	var ranges class
+	first := true
+	for p.more() && (p.peek() != ']' || first) {
+		itemStart := p.pos
+		lo, loClass, err := p.parseClassItem()
+		if err != nil {
+			return nil, err
+		}
+		first = false
+
+		// a - that is last in the class is a literal
+		if loClass == nil && p.peek() == '-' && p.pos+1 < len(p.pattern) && p.pattern[p.pos+1] != ']' {
+			p.pos++
+			hi, hiClass, err := p.parseClassItem()
+			if err != nil {
+				return nil, err
+			}
+			if hiClass != nil || hi < lo {
+				return nil, p.errorf(itemStart, "invalid character class range `%s`", p.pattern[itemStart:p.pos])
+			}
+			ranges = append(ranges, runeRange{lo, hi})
+			continue
+		}
+
+		if loClass != nil {
+			ranges = append(ranges, loClass...)
+		} else {
+			ranges = append(ranges, runeRange{lo, lo})
+		}
+	}
+	if !p.more() {
+		return nil, p.errorf(open, "missing closing ]")
+	}
+	p.pos++
+
+	ranges = ranges.normalize()
+	if negated {
+		ranges = ranges.negate()
+	}
+	return &node{kind: nodeClass, class: ranges}, nil
+}
+
+// parseClassItem reads one character of a class
+// escapes like \d that stand for several characters are returned as a class
+func (p *parser) parseClassItem() (rune, class, error) {
+	if p.peek() == '\\' {
+		escaped, err := p.parseClassEscape()
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(escaped) == 1 && escaped[0].lo == escaped[0].hi {
+			return escaped[0].lo, nil, nil
+		}
+		return 0, escaped, nil
+	}
+
+	r, size := utf8.DecodeRuneInString(p.pattern[p.pos:])
+	if r == utf8.RuneError && size == 1 {
+		return 0, nil, p.errorf(p.pos, "invalid UTF-8")
+	}
+	p.pos += size
+	return r, nil, nil
+}
+
+// isWordByte checks if a byte is an ascii word character, as \w and \b use it
+func isWordByte(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_'
+}
+
+// hexValue returns the value of a hex digit, -1 if it is not one
+func hexValue(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'f':
+		return int(c-'a') + 10
+	case c >= 'A' && c <= 'F':
+		return int(c-'A') + 10
+	}
+	return -1
+}
@@ -0,0 +1,134 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/CFdefense/compiler/src/asm"
+	"github.com/CFdefense/compiler/src/lexer"
+	"github.com/CFdefense/compiler/src/parser"
+)
+
+const ASM_TEST_DIR = "./test/asm/tests/"
+
+// TestCase is a single inline assembly validation test
+// ExpectedDiagnostics holds one line summaries of everything reported
+type TestCase struct {
+	TestName            string   `json:"test_name"`
+	TestDescription     string   `json:"description"`
+	TestContent         string   `json:"code"`
+	ExpectedDiagnostics []string `json:"expected_diagnostics"`
+}
+
+type TestResult struct {
+	TestCase TestCase
+	Result   bool
+	Error    string
+	Duration time.Duration
+}
+
+// function to iterate over all asm test cases
+// will lex, parse and validate each program and compare the diagnostics to expected
+func RunAsmTests(debug bool) []TestResult {
+	var test_results []TestResult
+	l := lexer.InitializeLexer(debug)
+	p := parser.InitializeParser(debug)
+	v := asm.InitializeValidator(debug)
+
+	// every phase reports into the validator's reporter
+	l.SetReporter(v.GetReporter())
+	p.SetReporter(v.GetReporter())
+
+	// get all asm json test files
+	files, err := os.ReadDir(ASM_TEST_DIR)
+	if err != nil {
+		log.Fatalf("Failed to read directory: %v", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		fullPath := filepath.Join(ASM_TEST_DIR, file.Name())
+		tests, err := process_json_file(fullPath)
+		if err != nil {
+			log.Printf("Error processing %s: %v", fullPath, err)
+			continue
+		}
+
+		for _, test := range tests {
+			testStart := time.Now()
+
+			// reset every phase in between uses
+			l.ResetLexer()
+			p.ResetParser()
+			v.ResetValidator()
+
+			l.SetContent(map[string]string{"test.txt": test.TestContent})
+			l.LexicalAnalysis("")
+			p.SyntaxAnalysis(l.GetTokenStream())
+			v.AsmValidation(p.GetAST())
+
+			result, errorMsg := compareResults(test, v)
+			if debug && !result {
+				log.Printf("[ASM] %s: %s", test.TestName, errorMsg)
+			}
+
+			test_results = append(test_results, TestResult{
+				TestCase: test,
+				Result:   result,
+				Error:    errorMsg,
+				Duration: time.Since(testStart),
+			})
+		}
+	}
+
+	return test_results
+}
+
+// compareResults checks the diagnostics against the test case
+// Returns (bool, string) where bool is success and string is error message
+func compareResults(test TestCase, v *asm.Validator) (bool, string) {
+	actual := v.GetReporter().GetDiagnostics()
+	summaries := make([]string, 0, len(actual))
+	for _, diagnostic := range actual {
+		summaries = append(summaries, diagnostic.Summary())
+	}
+	if len(summaries) != len(test.ExpectedDiagnostics) {
+		return false, fmt.Sprintf("Diagnostic count mismatch: expected %d, got %d %q",
+			len(test.ExpectedDiagnostics), len(summaries), summaries)
+	}
+	for i, summary := range summaries {
+		if summary != test.ExpectedDiagnostics[i] {
+			return false, fmt.Sprintf("Diagnostic %d mismatch: expected %q, got %q", i, test.ExpectedDiagnostics[i], summary)
+		}
+	}
+	return true, ""
+}
+
+// function to unmarshal json file into a slice of test cases
+func process_json_file(fullPath string) ([]TestCase, error) {
+	var tests []TestCase
+
+	jsonFile, err := os.Open(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open JSON file %s: %w", fullPath, err)
+	}
+	defer jsonFile.Close()
+
+	decoder := json.NewDecoder(jsonFile)
+	if err := decoder.Decode(&tests); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON in %s: %w", fullPath, err)
+	}
+
+	if len(tests) == 0 {
+		log.Printf("Warning: no test cases found in %s. Possible format mismatch?", fullPath)
+	}
+
+	return tests, nil
+}
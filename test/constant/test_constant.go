@@ -0,0 +1,159 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/CFdefense/compiler/src/constant"
+	"github.com/CFdefense/compiler/src/lexer"
+	"github.com/CFdefense/compiler/src/parser"
+)
+
+const CONSTANT_TEST_DIR = "./test/constant/tests/"
+
+// ConstantResult is the expected value of one constant declaration
+// Value is shown as Value.String() and Type as Value.TypeName()
+type ConstantResult struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  string `json:"type"`
+}
+
+// TestCase is a single constant evaluation test
+// ExpectedConstants lists every constant that folds, in any order
+// ExpectedDiagnostics holds one line summaries of everything reported
+type TestCase struct {
+	TestName            string           `json:"test_name"`
+	TestDescription     string           `json:"description"`
+	TestContent         string           `json:"code"`
+	ExpectedConstants   []ConstantResult `json:"expected_constants"`
+	ExpectedDiagnostics []string         `json:"expected_diagnostics"`
+}
+
+type TestResult struct {
+	TestCase TestCase
+	Result   bool
+	Error    string
+	Duration time.Duration
+}
+
+// function to iterate over all constant test cases
+// will lex, parse and fold each program and compare the constants to expected
+func RunConstantTests(debug bool) []TestResult {
+	var test_results []TestResult
+	l := lexer.InitializeLexer(debug)
+	p := parser.InitializeParser(debug)
+	e := constant.InitializeEvaluator(debug)
+
+	// every phase reports into the evaluator's reporter
+	l.SetReporter(e.GetReporter())
+	p.SetReporter(e.GetReporter())
+
+	// get all constant json test files
+	files, err := os.ReadDir(CONSTANT_TEST_DIR)
+	if err != nil {
+		log.Fatalf("Failed to read directory: %v", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		fullPath := filepath.Join(CONSTANT_TEST_DIR, file.Name())
+		tests, err := process_json_file(fullPath)
+		if err != nil {
+			log.Printf("Error processing %s: %v", fullPath, err)
+			continue
+		}
+
+		for _, test := range tests {
+			testStart := time.Now()
+
+			// reset every phase in between uses
+			l.ResetLexer()
+			p.ResetParser()
+			e.ResetEvaluator()
+
+			l.SetContent(map[string]string{"test.txt": test.TestContent})
+			l.LexicalAnalysis("")
+			p.SyntaxAnalysis(l.GetTokenStream())
+			e.ConstantEvaluation(p.GetAST())
+
+			result, errorMsg := compareResults(test, e)
+			if debug && !result {
+				log.Printf("[CON] %s: %s", test.TestName, errorMsg)
+			}
+
+			test_results = append(test_results, TestResult{
+				TestCase: test,
+				Result:   result,
+				Error:    errorMsg,
+				Duration: time.Since(testStart),
+			})
+		}
+	}
+
+	return test_results
+}
+
+// compareResults checks the folded constants and the diagnostics against the test case
+// Returns (bool, string) where bool is success and string is error message
+func compareResults(test TestCase, e *constant.Evaluator) (bool, string) {
+	constants := e.GetConstants()
+	if len(constants) != len(test.ExpectedConstants) {
+		return false, fmt.Sprintf("Constant count mismatch: expected %d, got %d", len(test.ExpectedConstants), len(constants))
+	}
+	for _, expected := range test.ExpectedConstants {
+		value, ok := constants[expected.Name]
+		if !ok {
+			return false, fmt.Sprintf("Constant %s was not folded", expected.Name)
+		}
+		if value.String() != expected.Value || value.TypeName() != expected.Type {
+			return false, fmt.Sprintf("Constant %s mismatch: expected %s (%s), got %s (%s)",
+				expected.Name, expected.Value, expected.Type, value.String(), value.TypeName())
+		}
+	}
+
+	actual := e.GetReporter().GetDiagnostics()
+	summaries := make([]string, 0, len(actual))
+	for _, diagnostic := range actual {
+		summaries = append(summaries, diagnostic.Summary())
+	}
+	if len(summaries) != len(test.ExpectedDiagnostics) {
+		return false, fmt.Sprintf("Diagnostic count mismatch: expected %d, got %d %q",
+			len(test.ExpectedDiagnostics), len(summaries), summaries)
+	}
+	for i, summary := range summaries {
+		if summary != test.ExpectedDiagnostics[i] {
+			return false, fmt.Sprintf("Diagnostic %d mismatch: expected %q, got %q", i, test.ExpectedDiagnostics[i], summary)
+		}
+	}
+	return true, ""
+}
+
+// function to unmarshal json file into a slice of test cases
+func process_json_file(fullPath string) ([]TestCase, error) {
+	var tests []TestCase
+
+	jsonFile, err := os.Open(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open JSON file %s: %w", fullPath, err)
+	}
+	defer jsonFile.Close()
+
+	decoder := json.NewDecoder(jsonFile)
+	if err := decoder.Decode(&tests); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON in %s: %w", fullPath, err)
+	}
+
+	if len(tests) == 0 {
+		log.Printf("Warning: no test cases found in %s. Possible format mismatch?", fullPath)
+	}
+
+	return tests, nil
+}
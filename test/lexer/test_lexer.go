@@ -3,27 +3,64 @@ package test
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"testing/iotest"
 	"time"
 
+	"github.com/CFdefense/compiler/src/diagnostics"
 	"github.com/CFdefense/compiler/src/lexer"
 )
 
 const LEXER_TEST_DIR = "./test/lexer/tests/"
 
 // TokenResult represents a single token in the expected result
+// Span is optional and only checked when present
+// Value is the decoded literal value as LiteralValue.String shows it, Radix the
+// base of an integer literal, both are only checked when present
 type TokenResult struct {
-	Type    string `json:"type"`
-	Content string `json:"content"`
+	Type    string     `json:"type"`
+	Content string     `json:"content"`
+	Span    *TokenSpan `json:"span"`
+	Value   *string    `json:"value"`
+	Radix   int        `json:"radix"`
 }
 
+// TokenSpan is the expected location of a token
+// File is only compared when given
+type TokenSpan struct {
+	File   string `json:"file"`
+	Row    int    `json:"row"`
+	Col    int    `json:"col"`
+	Start  int    `json:"start"`
+	End    int    `json:"end"`
+	EndRow int    `json:"end_row"`
+	EndCol int    `json:"end_col"`
+}
+
+// LexerOptions configures the lexer for a single test case
+// every option is off when not given, Columns is "runes" or "bytes"
+type LexerOptions struct {
+	UnicodeIdentifiers bool   `json:"unicode_identifiers"`
+	LegacyComments     bool   `json:"legacy_comments"`
+	Interpolation      bool   `json:"string_interpolation"`
+	Columns            string `json:"columns"`
+}
+
+// ExpectedDiagnostics holds one line summaries of the reported diagnostics
+// they are only checked when the test case lists them
+// Files replaces code with several named source files
 type TestCase struct {
-	TestName        string        `json:"test_name"`
-	TestDescription string        `json:"description"`
-	TestContent     string        `json:"code"`
-	ExpectedResult  []TokenResult `json:"result"`
+	TestName            string            `json:"test_name"`
+	TestDescription     string            `json:"description"`
+	TestContent         string            `json:"code"`
+	Files               map[string]string `json:"files"`
+	Options             LexerOptions      `json:"options"`
+	ExpectedResult      []TokenResult     `json:"result"`
+	ExpectedDiagnostics []string          `json:"expected_diagnostics"`
 }
 
 type TestResult struct {
@@ -46,6 +83,28 @@ func RunLexerTests(debug bool) []TestResult {
 	totalTests := 0
 	passedTests := 0
 
+	// the generated scanner tables must match the automata built from the token definitions
+	totalTests++
+	tableStart := time.Now()
+	tableResult := TestResult{
+		TestCase: TestCase{
+			TestName:        "Generated Scanner Tables",
+			TestDescription: "scanner_tables.go matches the automata built from TokenRegexDefs, run go generate ./src/lexer if not",
+		},
+		Result: true,
+	}
+	if err := lexer.VerifyScannerTables(); err != nil {
+		tableResult.Result = false
+		tableResult.Error = err.Error()
+	} else {
+		passedTests++
+	}
+	tableResult.Duration = time.Since(tableStart)
+	test_results = append(test_results, tableResult)
+
+	// source of every test case, streamed together once they have all run
+	var sources []string
+
 	// get all lexer json test files
 	files, err := os.ReadDir(LEXER_TEST_DIR)
 	if err != nil {
@@ -53,6 +112,7 @@ func RunLexerTests(debug bool) []TestResult {
 	}
 
 	// iterate over all json files and extract
+	var suites [][]TestCase
 	for _, file := range files {
 		// if file is a json file, process it
 		if !file.IsDir() && filepath.Ext(file.Name()) == ".json" {
@@ -62,67 +122,122 @@ func RunLexerTests(debug bool) []TestResult {
 				log.Printf("Error processing %s: %v", fullPath, err)
 				continue
 			}
+			suites = append(suites, tests)
+		}
+	}
+	suites = append(suites, invalidUTF8Tests)
+
+	for _, tests := range suites {
+		// execute tests and add results to test results
+		for _, test := range tests {
+			// Track individual test timing
+			testStart := time.Now()
+			totalTests++
+
+			// reset lexer in between uses
+			l.ResetLexer()
+			l.SetUnicodeIdentifiers(test.Options.UnicodeIdentifiers)
+			l.SetLegacyComments(test.Options.LegacyComments)
+			l.SetStringInterpolation(test.Options.Interpolation)
+			if test.Options.Columns == "bytes" {
+				l.SetColumnUnit(diagnostics.COLUMN_BYTES)
+			} else {
+				l.SetColumnUnit(diagnostics.COLUMN_RUNES)
+			}
 
-			// execute tests and add results to test results
-			for _, test := range tests {
-				// Track individual test timing
-				testStart := time.Now()
-				totalTests++
-
-				// reset lexer in between uses
-				l.ResetLexer()
-
-				// set test content
+			// set test content
+			if test.Files != nil {
+				l.SetContent(test.Files)
+			} else {
 				l.SetContent(map[string]string{"test.txt": test.TestContent})
+			}
 
-				// run lexical analysis
-				l.LexicalAnalysis("")
+			// run lexical analysis
+			l.LexicalAnalysis("")
 
-				// get results and compare to expected
-				token_stream_result := l.GetTokenStream()
+			// get results and compare to expected
+			token_stream_result := l.GetTokenStream()
 
-				result, errorMsg := compareTokens(token_stream_result, test.ExpectedResult)
+			result, errorMsg := compareTokens(token_stream_result, test.ExpectedResult)
+			if result && test.ExpectedDiagnostics != nil {
+				result, errorMsg = compareDiagnostics(l.GetReporter().GetDiagnostics(), test.ExpectedDiagnostics)
+			}
+			if result {
+				result, errorMsg = compareStreams(l, iotest.OneByteReader, debug)
+			}
+			if result {
+				result, errorMsg = compareRoundTrip(l)
+			}
+			sources = append(sources, test.TestContent)
 
-				// Track test result
-				if result {
-					passedTests++
-				}
+			// Track test result
+			if result {
+				passedTests++
+			}
 
-				// Calculate test duration
-				testDuration := time.Since(testStart)
-
-				// Debug output for failing tests
-				if !result {
-					fmt.Printf("DEBUG: Test '%s' failed\n", test.TestName)
-					fmt.Printf("DEBUG: Input: '%s'\n", test.TestContent)
-					fmt.Printf("DEBUG: Expected %d tokens, got %d tokens\n", len(test.ExpectedResult), len(token_stream_result))
-					fmt.Printf("DEBUG: Expected tokens:\n")
-					for i, expected := range test.ExpectedResult {
-						fmt.Printf("  %d: {type: %s, content: %s}\n", i, expected.Type, expected.Content)
-					}
-					fmt.Printf("DEBUG: Actual tokens:\n")
-					for i, actual := range token_stream_result {
-						fmt.Printf("  %d: {type: %s, content: %s}\n", i, actual.GetTokenType().String(), actual.GetTokenContent())
-					}
-					fmt.Printf("DEBUG: Error: %s\n", errorMsg)
+			// Calculate test duration
+			testDuration := time.Since(testStart)
+
+			// Debug output for failing tests
+			if !result {
+				fmt.Printf("DEBUG: Test '%s' failed\n", test.TestName)
+				fmt.Printf("DEBUG: Input: '%s'\n", test.TestContent)
+				fmt.Printf("DEBUG: Expected %d tokens, got %d tokens\n", len(test.ExpectedResult), len(token_stream_result))
+				fmt.Printf("DEBUG: Expected tokens:\n")
+				for i, expected := range test.ExpectedResult {
+					fmt.Printf("  %d: {type: %s, content: %s}\n", i, expected.Type, expected.Content)
 				}
-
-				// Create a TestResult instance
-				test_result := TestResult{
-					TestCase: test,
-					Result:   result,
-					Expected: test.ExpectedResult,
-					Actual:   token_stream_result,
-					Error:    errorMsg,
-					Duration: testDuration,
+				fmt.Printf("DEBUG: Actual tokens:\n")
+				for i, actual := range token_stream_result {
+					fmt.Printf("  %d: {type: %s, content: %s}\n", i, actual.GetTokenType().String(), actual.GetTokenContent())
 				}
+				fmt.Printf("DEBUG: Error: %s\n", errorMsg)
+			}
 
-				// add test result to test results
-				test_results = append(test_results, test_result)
+			// Create a TestResult instance
+			test_result := TestResult{
+				TestCase: test,
+				Result:   result,
+				Expected: test.ExpectedResult,
+				Actual:   token_stream_result,
+				Error:    errorMsg,
+				Duration: testDuration,
 			}
+
+			// add test result to test results
+			test_results = append(test_results, test_result)
 		}
 	}
 
+	// a source several stream buffers long, so the stream has to drop lexed source as it goes
+	totalTests++
+	largeStart := time.Now()
+	largeResult := TestResult{
+		TestCase: TestCase{
+			TestName:        "Streamed Large Source",
+			TestDescription: "every test case joined and repeated lexes the same streamed as it does in one pass",
+		},
+		Result: true,
+	}
+	// a long asm block makes the dropped source fall inside it, where lexing depends on the block start
+	joined := strings.Join(sources, "\n") + "\n"
+	asmBlock := "asm {\n" + strings.Repeat("    movq -8(%rbp), %rax # load\n    addq 1, %rax\n", 1024) + "}\n"
+	l.ResetLexer()
+	l.SetUnicodeIdentifiers(false)
+	l.SetLegacyComments(false)
+	l.SetStringInterpolation(false)
+	l.SetColumnUnit(diagnostics.COLUMN_RUNES)
+	l.SetContent(map[string]string{"large.txt": strings.Repeat(joined, 1+(256*1024)/len(joined)) + asmBlock})
+	l.LexicalAnalysis("")
+	if result, errorMsg := compareStreams(l, iotest.HalfReader, debug); result {
+		passedTests++
+	} else {
+		largeResult.Result = false
+		largeResult.Error = errorMsg
+	}
+	largeResult.Duration = time.Since(largeStart)
+	test_results = append(test_results, largeResult)
+
 	// Calculate overall timing
 	overallDuration := time.Since(overallStart)
 
@@ -139,6 +254,65 @@ func RunLexerTests(debug bool) []TestResult {
 	return test_results
 }
 
+// invalidUTF8Tests cover source that is not valid UTF-8, which a JSON test file cannot hold
+var invalidUTF8Tests = []TestCase{
+	{
+		TestName:        "Invalid UTF-8 Byte",
+		TestDescription: "A byte that starts no UTF-8 sequence is reported with its byte offset",
+		TestContent:     "a \xff b",
+		ExpectedResult: []TokenResult{
+			{Type: "T_IDENTIFIER", Content: "a"},
+			{Type: "T_UNKNOWN", Content: "\xff"},
+			{Type: "T_IDENTIFIER", Content: "b", Span: &TokenSpan{Row: 1, Col: 5, Start: 4, End: 5, EndRow: 1, EndCol: 6}},
+		},
+		ExpectedDiagnostics: []string{
+			"error[E0003] test.txt:1:3: invalid UTF-8 byte 0xff at byte offset 2",
+		},
+	},
+	{
+		TestName:        "Invalid UTF-8 Truncated Sequence",
+		TestDescription: "Every byte of a sequence cut short is reported on its own",
+		TestContent:     "x = \xe2\x82;",
+		ExpectedResult: []TokenResult{
+			{Type: "T_IDENTIFIER", Content: "x"},
+			{Type: "T_ASSIGN", Content: "="},
+			{Type: "T_UNKNOWN", Content: "\xe2"},
+			{Type: "T_UNKNOWN", Content: "\x82"},
+			{Type: "T_SEMICOLON", Content: ";", Span: &TokenSpan{Row: 1, Col: 7, Start: 6, End: 7, EndRow: 1, EndCol: 8}},
+		},
+		ExpectedDiagnostics: []string{
+			"error[E0003] test.txt:1:5: invalid UTF-8 byte 0xe2 at byte offset 4",
+			"error[E0003] test.txt:1:6: invalid UTF-8 byte 0x82 at byte offset 5",
+		},
+	},
+	{
+		TestName:        "Invalid UTF-8 In String",
+		TestDescription: "A string literal keeps its bytes and reports the invalid one",
+		TestContent:     "s = \"ok\xc3(\";",
+		ExpectedResult: []TokenResult{
+			{Type: "T_IDENTIFIER", Content: "s"},
+			{Type: "T_ASSIGN", Content: "="},
+			{Type: "T_STRING_LITERAL", Content: "\"ok\xc3(\""},
+			{Type: "T_SEMICOLON", Content: ";"},
+		},
+		ExpectedDiagnostics: []string{
+			"error[E0003] test.txt:1:8: invalid UTF-8 byte 0xc3 at byte offset 7",
+		},
+	},
+	{
+		TestName:        "Invalid UTF-8 In Comment",
+		TestDescription: "Latin-1 text in a comment is reported at the right row and column",
+		TestContent:     "/* ok\n caf\xe9 */ x",
+		ExpectedResult: []TokenResult{
+			{Type: "T_MULTI_LINE_COMMENT", Content: "/* ok\n caf\xe9 */"},
+			{Type: "T_IDENTIFIER", Content: "x", Span: &TokenSpan{Row: 2, Col: 10, Start: 15, End: 16, EndRow: 2, EndCol: 11}},
+		},
+		ExpectedDiagnostics: []string{
+			"error[E0003] test.txt:2:5: invalid UTF-8 byte 0xe9 at byte offset 10",
+		},
+	},
+}
+
 // compareTokens compares a slice of tokens with a slice of expected token results
 // Returns (bool, string) where bool is success and string is error message
 func compareTokens(actual []lexer.Token, expected []TokenResult) (bool, string) {
@@ -161,6 +335,114 @@ func compareTokens(actual []lexer.Token, expected []TokenResult) (bool, string)
 			return false, fmt.Sprintf("Token %d mismatch: expected {type: %s, content: %s}, got {type: %s, content: %s}",
 				i, expectedType, expectedContent, actualType, actualContent)
 		}
+
+		if expected[i].Span != nil {
+			actualSpan := TokenSpan{
+				Row:    token.GetRow(),
+				Col:    token.GetCol(),
+				Start:  token.GetStart(),
+				End:    token.GetEnd(),
+				EndRow: token.GetEndRow(),
+				EndCol: token.GetEndCol(),
+			}
+			if expected[i].Span.File != "" {
+				actualSpan.File = token.GetFile()
+			}
+			if actualSpan != *expected[i].Span {
+				return false, fmt.Sprintf("Token %d span mismatch: expected %+v, got %+v", i, *expected[i].Span, actualSpan)
+			}
+		}
+
+		value := token.GetValue()
+		if expected[i].Value != nil {
+			if value == nil {
+				return false, fmt.Sprintf("Token %d has no value, expected %q", i, *expected[i].Value)
+			}
+			if value.String() != *expected[i].Value {
+				return false, fmt.Sprintf("Token %d value mismatch: expected %q, got %q", i, *expected[i].Value, value.String())
+			}
+		}
+		if expected[i].Radix != 0 {
+			radix := 0
+			if value != nil {
+				radix = value.GetRadix()
+			}
+			if radix != expected[i].Radix {
+				return false, fmt.Sprintf("Token %d radix mismatch: expected %d, got %d", i, expected[i].Radix, radix)
+			}
+		}
+	}
+
+	return true, ""
+}
+
+// compareStreams lexes every file of an analyzed lexer again through a TokenStream
+// wrap controls how the source is read, the streamed tokens must match the batch tokens exactly
+func compareStreams(l *lexer.Lexer, wrap func(io.Reader) io.Reader, debug bool) (bool, string) {
+	// a separate lexer keeps streamed diagnostics out of the batch results
+	streamer := lexer.InitializeLexer(debug)
+	streamer.SetUnicodeIdentifiers(l.GetUnicodeIdentifiers())
+	streamer.SetLegacyComments(l.GetLegacyComments())
+	streamer.SetStringInterpolation(l.GetStringInterpolation())
+	streamer.SetColumnUnit(l.GetColumnUnit())
+
+	for _, file := range l.GetFiles() {
+		expected := l.GetFileTokenStream(file)
+		reader := wrap(strings.NewReader(l.GetContent()[file]))
+
+		i := 0
+		for token := range streamer.Stream(file, reader).All() {
+			if i >= len(expected) {
+				return false, fmt.Sprintf("Stream of %s produced more than %d tokens", file, len(expected))
+			}
+			if !token.Equal(expected[i]) {
+				return false, fmt.Sprintf("Stream of %s token %d mismatch: expected %+v, got %+v", file, i, expected[i], token)
+			}
+			i++
+		}
+		if i != len(expected) {
+			return false, fmt.Sprintf("Stream of %s token count mismatch: expected %d, got %d", file, len(expected), i)
+		}
+	}
+
+	return true, ""
+}
+
+// compareRoundTrip checks that the tokens of every file give back its source
+// each token carries the whitespace around it as trivia, so nothing is lost
+func compareRoundTrip(l *lexer.Lexer) (bool, string) {
+	for _, file := range l.GetFiles() {
+		var text strings.Builder
+		for _, token := range l.GetFileTokenStream(file) {
+			text.WriteString(token.GetFullText())
+		}
+		content := l.GetContent()[file]
+		// a file of nothing but whitespace has no token to hold it
+		if strings.Trim(content, " \t\r\n") == "" {
+			content = ""
+		}
+		if text.String() != content {
+			return false, fmt.Sprintf("Round trip of %s mismatch: expected %q, got %q", file, content, text.String())
+		}
+	}
+	return true, ""
+}
+
+// compareDiagnostics compares reported diagnostics with their expected summaries
+// Returns (bool, string) where bool is success and string is error message
+func compareDiagnostics(actual []*diagnostics.Diagnostic, expected []string) (bool, string) {
+	if len(actual) != len(expected) {
+		summaries := make([]string, 0, len(actual))
+		for _, diagnostic := range actual {
+			summaries = append(summaries, diagnostic.Summary())
+		}
+		return false, fmt.Sprintf("Diagnostic count mismatch: expected %d, got %d %q", len(expected), len(actual), summaries)
+	}
+
+	for i, diagnostic := range actual {
+		if diagnostic.Summary() != expected[i] {
+			return false, fmt.Sprintf("Diagnostic %d mismatch: expected %q, got %q", i, expected[i], diagnostic.Summary())
+		}
 	}
 
 	return true, ""
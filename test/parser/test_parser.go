@@ -1 +1,188 @@
 package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/CFdefense/compiler/src/diagnostics"
+	"github.com/CFdefense/compiler/src/lexer"
+	"github.com/CFdefense/compiler/src/parser"
+)
+
+const PARSER_TEST_DIR = "./test/parser/tests/"
+
+// TestCase is a single parser test
+// ExpectedAST is the s-expression form of the tree (Program.String())
+// ExpectedErrors lists the syntax errors, none are expected when empty
+// ExpectedWarnings lists the parser's warnings the same way
+// ExpectedLexerErrors lists the lexer's errors the same way, the parser must not report them again
+// ExpectedCST is the s-expression form of the concrete syntax tree, left out by most tests
+type TestCase struct {
+	TestName         string   `json:"test_name"`
+	TestDescription  string   `json:"description"`
+	TestContent      string   `json:"code"`
+	ExpectedAST      string   `json:"expected_ast"`
+	ExpectedErrors   []string `json:"expected_errors"`
+	ExpectedWarnings []string `json:"expected_warnings"`
+	ExpectedLexer    []string `json:"expected_lexer_errors"`
+	ExpectedCST      string   `json:"expected_cst"`
+}
+
+type TestResult struct {
+	TestCase     TestCase
+	Result       bool
+	ActualAST    string
+	ActualErrors []string
+	ActualCST    string
+	Error        string
+	Duration     time.Duration
+}
+
+// function to iterate over all parser test cases
+// will compare the produced tree and errors to expected
+func RunParserTests(debug bool) []TestResult {
+	var test_results []TestResult
+	l := lexer.InitializeLexer(debug)
+	p := parser.InitializeParser(debug)
+
+	// get all parser json test files
+	files, err := os.ReadDir(PARSER_TEST_DIR)
+	if err != nil {
+		log.Fatalf("Failed to read directory: %v", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		fullPath := filepath.Join(PARSER_TEST_DIR, file.Name())
+		tests, err := process_json_file(fullPath)
+		if err != nil {
+			log.Printf("Error processing %s: %v", fullPath, err)
+			continue
+		}
+
+		for _, test := range tests {
+			testStart := time.Now()
+
+			// reset lexer and parser in between uses
+			l.ResetLexer()
+			p.ResetParser()
+
+			l.SetContent(map[string]string{"test.txt": test.TestContent})
+			l.LexicalAnalysis("")
+			p.SetEndTrivia(l.GetEndTrivia("test.txt"))
+			p.SyntaxAnalysis(l.GetTokenStream())
+
+			actualAST := p.GetAST().String()
+			actualErrors := p.GetErrors()
+			result, errorMsg := compareResults(test, actualAST, actualErrors)
+			if result {
+				result, errorMsg = compareDiagnostics("Warning", test.ExpectedWarnings,
+					p.GetReporter().GetDiagnostics(), diagnostics.SEVERITY_WARNING)
+			}
+			if result {
+				result, errorMsg = compareDiagnostics("Lexer error", test.ExpectedLexer,
+					l.GetReporter().GetDiagnostics(), diagnostics.SEVERITY_ERROR)
+			}
+			actualCST := p.GetCST().String()
+			if result {
+				result, errorMsg = compareCST(test, p.GetCST())
+			}
+
+			test_results = append(test_results, TestResult{
+				TestCase:     test,
+				Result:       result,
+				ActualAST:    actualAST,
+				ActualErrors: actualErrors,
+				ActualCST:    actualCST,
+				Error:        errorMsg,
+				Duration:     time.Since(testStart),
+			})
+		}
+	}
+
+	return test_results
+}
+
+// compareResults checks the tree and the error list against the test case
+// Returns (bool, string) where bool is success and string is error message
+func compareResults(test TestCase, actualAST string, actualErrors []string) (bool, string) {
+	if len(actualErrors) != len(test.ExpectedErrors) {
+		return false, fmt.Sprintf("Error count mismatch: expected %d, got %d %v",
+			len(test.ExpectedErrors), len(actualErrors), actualErrors)
+	}
+	for i, err := range actualErrors {
+		if err != test.ExpectedErrors[i] {
+			return false, fmt.Sprintf("Error %d mismatch: expected %q, got %q", i, test.ExpectedErrors[i], err)
+		}
+	}
+
+	// a test that only checks errors may leave the tree out
+	if test.ExpectedAST != "" && actualAST != test.ExpectedAST {
+		return false, "AST mismatch"
+	}
+	return true, ""
+}
+
+// compareCST checks that the concrete syntax tree gives back the source of every test
+// and checks its form against the tests that have one
+func compareCST(test TestCase, cst *parser.SyntaxNode) (bool, string) {
+	source := test.TestContent
+	if text := cst.Text(); text != source {
+		return false, fmt.Sprintf("CST round trip mismatch: expected %q, got %q", source, text)
+	}
+	if test.ExpectedCST != "" && cst.String() != test.ExpectedCST {
+		return false, "CST mismatch"
+	}
+	return true, ""
+}
+
+// compareDiagnostics checks the diagnostics of one severity against the expected list
+// they are written like errors, as row:col: message
+func compareDiagnostics(kind string, expected []string, reported []*diagnostics.Diagnostic, severity diagnostics.Severity) (bool, string) {
+	var actual []string
+	for _, diagnostic := range reported {
+		if diagnostic.Severity == severity {
+			actual = append(actual, fmt.Sprintf("%d:%d: %s",
+				diagnostic.Primary.Row, diagnostic.Primary.Col, diagnostic.Message))
+		}
+	}
+	if len(actual) != len(expected) {
+		return false, fmt.Sprintf("%s count mismatch: expected %d, got %d %v",
+			kind, len(expected), len(actual), actual)
+	}
+	for i, message := range actual {
+		if message != expected[i] {
+			return false, fmt.Sprintf("%s %d mismatch: expected %q, got %q", kind, i, expected[i], message)
+		}
+	}
+	return true, ""
+}
+
+// function to unmarshal json file into a slice of test cases
+func process_json_file(fullPath string) ([]TestCase, error) {
+	var tests []TestCase
+
+	jsonFile, err := os.Open(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open JSON file %s: %w", fullPath, err)
+	}
+	defer jsonFile.Close()
+
+	decoder := json.NewDecoder(jsonFile)
+	if err := decoder.Decode(&tests); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON in %s: %w", fullPath, err)
+	}
+
+	if len(tests) == 0 {
+		log.Printf("Warning: no test cases found in %s. Possible format mismatch?", fullPath)
+	}
+
+	return tests, nil
+}
@@ -0,0 +1,136 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/CFdefense/compiler/src/regex"
+)
+
+const REGEX_TEST_DIR = "./test/regex/tests/"
+
+// InputResult is the expected outcome of running a pattern on one input
+// Match is the result of MatchString, Prefix the length FindPrefix returns
+// or -1 when the input does not start with a match
+type InputResult struct {
+	Input  string `json:"input"`
+	Match  bool   `json:"match"`
+	Prefix int    `json:"prefix"`
+}
+
+// TestCase is a single regex test
+// ExpectedError is the compile error, the pattern must compile when it is empty
+type TestCase struct {
+	TestName        string        `json:"test_name"`
+	TestDescription string        `json:"description"`
+	Pattern         string        `json:"pattern"`
+	ExpectedError   string        `json:"expected_error"`
+	Inputs          []InputResult `json:"inputs"`
+}
+
+type TestResult struct {
+	TestCase TestCase
+	Result   bool
+	Error    string
+	Duration time.Duration
+}
+
+// function to iterate over all regex test cases
+// will compile each pattern and compare its matches to expected
+func RunRegexTests(debug bool) []TestResult {
+	var test_results []TestResult
+
+	// get all regex json test files
+	files, err := os.ReadDir(REGEX_TEST_DIR)
+	if err != nil {
+		log.Fatalf("Failed to read directory: %v", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		fullPath := filepath.Join(REGEX_TEST_DIR, file.Name())
+		tests, err := process_json_file(fullPath)
+		if err != nil {
+			log.Printf("Error processing %s: %v", fullPath, err)
+			continue
+		}
+
+		for _, test := range tests {
+			testStart := time.Now()
+			result, errorMsg := runTest(test)
+			if debug && !result {
+				log.Printf("[REG] %s: %s", test.TestName, errorMsg)
+			}
+
+			test_results = append(test_results, TestResult{
+				TestCase: test,
+				Result:   result,
+				Error:    errorMsg,
+				Duration: time.Since(testStart),
+			})
+		}
+	}
+
+	return test_results
+}
+
+// runTest compiles the pattern of a test case and checks every input
+// Returns (bool, string) where bool is success and string is error message
+func runTest(test TestCase) (bool, string) {
+	re, err := regex.Compile(test.Pattern)
+	if test.ExpectedError != "" {
+		if err == nil {
+			return false, fmt.Sprintf("Expected error %q, pattern compiled", test.ExpectedError)
+		}
+		if err.Error() != test.ExpectedError {
+			return false, fmt.Sprintf("Error mismatch: expected %q, got %q", test.ExpectedError, err.Error())
+		}
+		return true, ""
+	}
+	if err != nil {
+		return false, fmt.Sprintf("Unexpected error: %v", err)
+	}
+
+	for _, input := range test.Inputs {
+		if match := re.MatchString(input.Input); match != input.Match {
+			return false, fmt.Sprintf("MatchString(%q): expected %v, got %v", input.Input, input.Match, match)
+		}
+		prefix, ok := re.FindPrefix(input.Input)
+		if !ok {
+			prefix = -1
+		}
+		if prefix != input.Prefix {
+			return false, fmt.Sprintf("FindPrefix(%q): expected %d, got %d", input.Input, input.Prefix, prefix)
+		}
+	}
+	return true, ""
+}
+
+// function to unmarshal json file into a slice of test cases
+func process_json_file(fullPath string) ([]TestCase, error) {
+	var tests []TestCase
+
+	jsonFile, err := os.Open(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open JSON file %s: %w", fullPath, err)
+	}
+	defer jsonFile.Close()
+
+	decoder := json.NewDecoder(jsonFile)
+	if err := decoder.Decode(&tests); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON in %s: %w", fullPath, err)
+	}
+
+	if len(tests) == 0 {
+		log.Printf("Warning: no test cases found in %s. Possible format mismatch?", fullPath)
+	}
+
+	return tests, nil
+}
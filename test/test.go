@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"time"
 
+	asm_test "github.com/CFdefense/compiler/test/asm"
+	constant_test "github.com/CFdefense/compiler/test/constant"
 	lexer_test "github.com/CFdefense/compiler/test/lexer"
+	parser_test "github.com/CFdefense/compiler/test/parser"
+	regex_test "github.com/CFdefense/compiler/test/regex"
 )
 
 // function to run all tests
@@ -12,19 +16,11 @@ func RunTests(debug bool) {
 	startTime := time.Now()
 	lexer_tests := lexer_test.RunLexerTests(debug)
 
-	fmt.Println("Lexer Tests:")
-	fmt.Println("==========================================")
-
-	passed := 0
-	failed := 0
-	totalDuration := time.Duration(0)
-
-	for _, test := range lexer_tests {
-		if test.Result {
-			fmt.Printf("%s PASSED (%v)\n", test.TestCase.TestName, test.Duration)
-			passed++
-		} else {
-			fmt.Printf("%s FAILED (%v)\n", test.TestCase.TestName, test.Duration)
+	printTests("Lexer", "lexer", startTime, lexer_tests,
+		func(test lexer_test.TestResult) (string, bool, time.Duration) {
+			return test.TestCase.TestName, test.Result, test.Duration
+		},
+		func(test lexer_test.TestResult) {
 			fmt.Printf("   Description: %s\n", test.TestCase.TestDescription)
 			fmt.Printf("   Input: %s\n", test.TestCase.TestContent)
 			fmt.Printf("   Error: %s\n", test.Error)
@@ -43,9 +39,105 @@ func RunTests(debug bool) {
 						i, token.GetTokenType().String(), token.GetTokenContent())
 				}
 			}
+		})
+}
+
+// function to run all parser tests
+func RunParserTests(debug bool) {
+	startTime := time.Now()
+	parser_tests := parser_test.RunParserTests(debug)
+
+	printTests("Parser", "parser", startTime, parser_tests,
+		func(test parser_test.TestResult) (string, bool, time.Duration) {
+			return test.TestCase.TestName, test.Result, test.Duration
+		},
+		func(test parser_test.TestResult) {
+			fmt.Printf("   Description: %s\n", test.TestCase.TestDescription)
+			fmt.Printf("   Input: %s\n", test.TestCase.TestContent)
+			fmt.Printf("   Error: %s\n", test.Error)
+			fmt.Printf("   Expected AST: %s\n", test.TestCase.ExpectedAST)
+			fmt.Printf("   Actual AST:   %s\n", test.ActualAST)
+			if test.TestCase.ExpectedCST != "" {
+				fmt.Printf("   Expected CST: %s\n", test.TestCase.ExpectedCST)
+				fmt.Printf("   Actual CST:   %s\n", test.ActualCST)
+			}
+			for _, err := range test.ActualErrors {
+				fmt.Printf("   Syntax error: %s\n", err)
+			}
+		})
+}
+
+// function to run all regex engine tests
+func RunRegexTests(debug bool) {
+	startTime := time.Now()
+	regex_tests := regex_test.RunRegexTests(debug)
+
+	printTests("Regex", "regex", startTime, regex_tests,
+		func(test regex_test.TestResult) (string, bool, time.Duration) {
+			return test.TestCase.TestName, test.Result, test.Duration
+		},
+		func(test regex_test.TestResult) {
+			fmt.Printf("   Description: %s\n", test.TestCase.TestDescription)
+			fmt.Printf("   Pattern: %s\n", test.TestCase.Pattern)
+			fmt.Printf("   Error: %s\n", test.Error)
+		})
+}
+
+// function to run all constant evaluation tests
+func RunConstantTests(debug bool) {
+	startTime := time.Now()
+	constant_tests := constant_test.RunConstantTests(debug)
+
+	printTests("Constant", "constant evaluator", startTime, constant_tests,
+		func(test constant_test.TestResult) (string, bool, time.Duration) {
+			return test.TestCase.TestName, test.Result, test.Duration
+		},
+		func(test constant_test.TestResult) {
+			fmt.Printf("   Description: %s\n", test.TestCase.TestDescription)
+			fmt.Printf("   Input: %s\n", test.TestCase.TestContent)
+			fmt.Printf("   Error: %s\n", test.Error)
+		})
+}
+
+// function to run all inline assembly validation tests
+func RunAsmTests(debug bool) {
+	startTime := time.Now()
+	asm_tests := asm_test.RunAsmTests(debug)
+
+	printTests("Asm", "asm validator", startTime, asm_tests,
+		func(test asm_test.TestResult) (string, bool, time.Duration) {
+			return test.TestCase.TestName, test.Result, test.Duration
+		},
+		func(test asm_test.TestResult) {
+			fmt.Printf("   Description: %s\n", test.TestCase.TestDescription)
+			fmt.Printf("   Input: %s\n", test.TestCase.TestContent)
+			fmt.Printf("   Error: %s\n", test.Error)
+		})
+}
+
+// printTests prints the result of every test of a suite and the summary
+// outcome gives the name, result and duration of a test, details prints what
+// is shown about a test that failed. implementation is what a failure points at
+func printTests[T any](suite, implementation string, startTime time.Time, tests []T,
+	outcome func(T) (string, bool, time.Duration), details func(T)) {
+	fmt.Printf("%s Tests:\n", suite)
+	fmt.Println("==========================================")
+
+	passed := 0
+	failed := 0
+	totalDuration := time.Duration(0)
+
+	for _, test := range tests {
+		name, result, duration := outcome(test)
+		if result {
+			fmt.Printf("%s PASSED (%v)\n", name, duration)
+			passed++
+		} else {
+			fmt.Printf("%s FAILED (%v)\n", name, duration)
+			details(test)
 			failed++
 		}
-		totalDuration += test.Duration
+		totalDuration += duration
 		fmt.Print("------------------------------------------\n")
 	}
 
@@ -54,10 +146,12 @@ func RunTests(debug bool) {
 	fmt.Printf("\nTest Summary: %d passed, %d failed\n", passed, failed)
 	fmt.Printf("Total test execution time: %v\n", totalDuration)
 	fmt.Printf("Overall time (including setup): %v\n", overallDuration)
-	fmt.Printf("Average time per test: %v\n", totalDuration/time.Duration(passed+failed))
+	if passed+failed > 0 {
+		fmt.Printf("Average time per test: %v\n", totalDuration/time.Duration(passed+failed))
+	}
 
 	if failed > 0 {
-		fmt.Println("Some tests failed - check the lexer implementation")
+		fmt.Printf("Some tests failed - check the %s implementation\n", implementation)
 	} else {
 		fmt.Println("All tests passed!")
 	}